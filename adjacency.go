@@ -0,0 +1,103 @@
+// ABOUTME: Maintains the adjacency index that backs neighbor queries on a Graph.
+// ABOUTME: Provides successor, predecessor, neighbor, degree and edge lookup by node ID.
+package goraffe
+
+import "slices"
+
+// indexEdge records an edge in the adjacency index.
+func (g *Graph) indexEdge(e *Edge) {
+	g.outEdges[e.from.ID()] = append(g.outEdges[e.from.ID()], e)
+	g.inEdges[e.to.ID()] = append(g.inEdges[e.to.ID()], e)
+}
+
+// unindexEdge removes an edge from the adjacency index.
+func (g *Graph) unindexEdge(e *Edge) {
+	g.outEdges[e.from.ID()] = slices.DeleteFunc(g.outEdges[e.from.ID()], func(candidate *Edge) bool {
+		return candidate == e
+	})
+	g.inEdges[e.to.ID()] = slices.DeleteFunc(g.inEdges[e.to.ID()], func(candidate *Edge) bool {
+		return candidate == e
+	})
+}
+
+// Successors returns the distinct nodes reached by edges leaving the node with the given ID,
+// in the order the edges were added. Edges are followed from From to To as declared,
+// so for undirected graphs Neighbors is usually the more useful query.
+// Returns an empty slice if the node has no outgoing edges or does not exist.
+func (g *Graph) Successors(id string) []*Node {
+	return g.appendEndpoints(make([]*Node, 0), make(map[string]bool), g.outEdges[id], (*Edge).To)
+}
+
+// Predecessors returns the distinct nodes with edges arriving at the node with the given ID,
+// in the order the edges were added. Edges are followed from From to To as declared.
+// Returns an empty slice if the node has no incoming edges or does not exist.
+func (g *Graph) Predecessors(id string) []*Node {
+	return g.appendEndpoints(make([]*Node, 0), make(map[string]bool), g.inEdges[id], (*Edge).From)
+}
+
+// Neighbors returns the distinct nodes joined to the node with the given ID by an edge
+// in either orientation: successors first, followed by any remaining predecessors.
+// A node with a self-loop is its own neighbor.
+// Returns an empty slice if the node has no edges or does not exist.
+func (g *Graph) Neighbors(id string) []*Node {
+	seen := make(map[string]bool)
+	nodes := g.appendEndpoints(make([]*Node, 0), seen, g.outEdges[id], (*Edge).To)
+
+	return g.appendEndpoints(nodes, seen, g.inEdges[id], (*Edge).From)
+}
+
+// OutDegree returns the number of edges leaving the node with the given ID.
+// Parallel edges are counted individually.
+func (g *Graph) OutDegree(id string) int {
+	return len(g.outEdges[id])
+}
+
+// InDegree returns the number of edges arriving at the node with the given ID.
+// Parallel edges are counted individually.
+func (g *Graph) InDegree(id string) int {
+	return len(g.inEdges[id])
+}
+
+// EdgesBetween returns every edge from the node with ID a to the node with ID b,
+// in the order they were added. In undirected graphs, edges from b to a are also
+// included, after those from a to b.
+// Returns an empty slice if the nodes are not connected.
+func (g *Graph) EdgesBetween(a, b string) []*Edge {
+	edges := make([]*Edge, 0)
+
+	for _, e := range g.outEdges[a] {
+		if e.to.ID() == b {
+			edges = append(edges, e)
+		}
+	}
+
+	// A self-loop already appeared in the outgoing pass
+	if g.directed || a == b {
+		return edges
+	}
+
+	for _, e := range g.inEdges[a] {
+		if e.from.ID() == b {
+			edges = append(edges, e)
+		}
+	}
+
+	return edges
+}
+
+// appendEndpoints appends the canonical graph node at the chosen end of each edge to
+// nodes, skipping any node already recorded in seen.
+func (g *Graph) appendEndpoints(
+	nodes []*Node, seen map[string]bool, edges []*Edge, endpoint func(*Edge) *Node,
+) []*Node {
+	for _, e := range edges {
+		id := endpoint(e).ID()
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		nodes = append(nodes, g.GetNode(id))
+	}
+
+	return nodes
+}
@@ -0,0 +1,153 @@
+// ABOUTME: Tests for the adjacency index and neighbor queries on Graph.
+// ABOUTME: Verifies successors, predecessors, neighbors, degrees and edge lookup stay in sync.
+package goraffe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func nodeIDs(nodes []*Node) []string {
+	ids := make([]string, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID()
+	}
+	return ids
+}
+
+func TestGraph_Successors(t *testing.T) {
+	asrt := assert.New(t)
+
+	g := NewGraph(Directed)
+	a, b, c := NewNode("A"), NewNode("B"), NewNode("C")
+	_, _ = g.AddEdge(a, c)
+	_, _ = g.AddEdge(a, b)
+	_, _ = g.AddEdge(a, c)
+	_, _ = g.AddEdge(b, a)
+
+	asrt.Equal([]string{"C", "B"}, nodeIDs(g.Successors("A")), "expected distinct successors in edge order")
+	asrt.Equal([]string{"A"}, nodeIDs(g.Successors("B")), "expected B's single successor")
+	asrt.Empty(g.Successors("C"), "expected no successors for sink node")
+	asrt.Empty(g.Successors("Z"), "expected no successors for unknown node")
+}
+
+func TestGraph_Predecessors(t *testing.T) {
+	asrt := assert.New(t)
+
+	g := NewGraph(Directed)
+	a, b, c := NewNode("A"), NewNode("B"), NewNode("C")
+	_, _ = g.AddEdge(b, c)
+	_, _ = g.AddEdge(a, c)
+	_, _ = g.AddEdge(b, c)
+
+	asrt.Equal([]string{"B", "A"}, nodeIDs(g.Predecessors("C")), "expected distinct predecessors in edge order")
+	asrt.Empty(g.Predecessors("A"), "expected no predecessors for source node")
+}
+
+func TestGraph_Neighbors(t *testing.T) {
+	t.Run("undirected graph", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Undirected)
+		a, b, c := NewNode("A"), NewNode("B"), NewNode("C")
+		_, _ = g.AddEdge(a, b)
+		_, _ = g.AddEdge(c, a)
+		_, _ = g.AddEdge(b, a)
+
+		asrt.Equal([]string{"B", "C"}, nodeIDs(g.Neighbors("A")), "expected neighbors regardless of orientation")
+	})
+
+	t.Run("self-loop", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Undirected)
+		a := NewNode("A")
+		_, _ = g.AddEdge(a, a)
+
+		asrt.Equal([]string{"A"}, nodeIDs(g.Neighbors("A")), "expected node to neighbor itself once")
+	})
+}
+
+func TestGraph_Neighbors_ReturnsCanonicalNodes(t *testing.T) {
+	asrt := assert.New(t)
+
+	g := NewGraph(Directed)
+	_, _ = g.AddEdge(NewNode("A"), NewNode("B"))
+	replacement := NewNode("B", WithLabel("replacement"))
+	_ = g.AddNode(replacement)
+
+	asrt.Same(replacement, g.Successors("A")[0], "expected successors to resolve to the graph's node")
+}
+
+func TestGraph_Degree(t *testing.T) {
+	asrt := assert.New(t)
+
+	g := NewGraph(Directed)
+	a, b, c := NewNode("A"), NewNode("B"), NewNode("C")
+	_, _ = g.AddEdge(a, b)
+	_, _ = g.AddEdge(a, b)
+	_, _ = g.AddEdge(a, c)
+	_, _ = g.AddEdge(c, a)
+
+	asrt.Equal(3, g.OutDegree("A"), "expected parallel edges to count individually")
+	asrt.Equal(1, g.InDegree("A"), "expected one incoming edge")
+	asrt.Equal(2, g.InDegree("B"), "expected two incoming edges")
+	asrt.Equal(0, g.OutDegree("B"), "expected no outgoing edges")
+	asrt.Equal(0, g.InDegree("Z"), "expected zero degree for unknown node")
+}
+
+func TestGraph_EdgesBetween(t *testing.T) {
+	t.Run("directed graph", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		a, b := NewNode("A"), NewNode("B")
+		e1, _ := g.AddEdge(a, b)
+		_, _ = g.AddEdge(b, a)
+		e3, _ := g.AddEdge(a, b)
+
+		asrt.Equal([]*Edge{e1, e3}, g.EdgesBetween("A", "B"), "expected only A->B edges")
+	})
+
+	t.Run("undirected graph", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Undirected)
+		a, b := NewNode("A"), NewNode("B")
+		e1, _ := g.AddEdge(a, b)
+		e2, _ := g.AddEdge(b, a)
+
+		asrt.Equal([]*Edge{e1, e2}, g.EdgesBetween("A", "B"), "expected edges in both orientations")
+		asrt.Equal([]*Edge{e2, e1}, g.EdgesBetween("B", "A"), "expected edges from B first")
+	})
+
+	t.Run("undirected self-loop is returned once", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Undirected)
+		a := NewNode("A")
+		e, _ := g.AddEdge(a, a)
+
+		asrt.Equal([]*Edge{e}, g.EdgesBetween("A", "A"), "expected self-loop once")
+	})
+}
+
+func TestGraph_AdjacencyIndex_StaysInSyncWithRemoval(t *testing.T) {
+	asrt := assert.New(t)
+
+	g := NewGraph(Directed)
+	a, b, c := NewNode("A"), NewNode("B"), NewNode("C")
+	e1, _ := g.AddEdge(a, b)
+	_, _ = g.AddEdge(b, c)
+	_, _ = g.AddEdge(a, c)
+
+	g.RemoveEdge(e1)
+	asrt.Equal([]string{"C"}, nodeIDs(g.Successors("A")), "expected removed edge to leave the index")
+	asrt.Empty(g.Predecessors("B"), "expected B to lose its predecessor")
+
+	g.RemoveNode("C")
+	asrt.Empty(g.Successors("A"), "expected edges to removed node to leave the index")
+	asrt.Equal(0, g.OutDegree("B"), "expected B's outgoing edge to be removed")
+	asrt.Empty(g.EdgesBetween("B", "C"), "expected no edges to removed node")
+}
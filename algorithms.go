@@ -0,0 +1,320 @@
+// ABOUTME: Implements graph algorithms over Graph: topological sort, cycle detection and SCCs.
+// ABOUTME: Traversals follow insertion order so results are stable across runs.
+package goraffe
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrCycle is returned when an operation that requires an acyclic graph encounters a cycle.
+// The concrete error is a *CycleError carrying the offending path.
+var ErrCycle = errors.New("graph contains a cycle")
+
+// ErrUndirectedGraph is returned when an operation that requires a directed graph
+// is called on an undirected one.
+var ErrUndirectedGraph = errors.New("operation requires a directed graph")
+
+// CycleError reports a cycle found while processing a graph.
+// It unwraps to ErrCycle, so callers can use errors.Is(err, ErrCycle).
+type CycleError struct {
+	// Cycle lists the nodes of the cycle in traversal order.
+	// The last node has an edge back to the first.
+	Cycle []*Node
+}
+
+// Error implements the error interface.
+func (e *CycleError) Error() string {
+	ids := make([]string, 0, len(e.Cycle)+1)
+	for _, n := range e.Cycle {
+		ids = append(ids, n.ID())
+	}
+	if len(e.Cycle) > 0 {
+		ids = append(ids, e.Cycle[0].ID())
+	}
+
+	return fmt.Sprintf("%v: %s", ErrCycle, strings.Join(ids, " -> "))
+}
+
+// Unwrap returns ErrCycle.
+func (e *CycleError) Unwrap() error {
+	return ErrCycle
+}
+
+// TopologicalSort returns the nodes of a directed graph ordered so that every edge points
+// from an earlier node to a later one. Ties are broken by the insertion order of Nodes(),
+// so the result is stable across runs.
+// Returns ErrUndirectedGraph for undirected graphs, and a *CycleError if the graph
+// contains a cycle (including a self-loop).
+//
+// Example:
+//
+//	order, err := g.TopologicalSort()
+//	if errors.Is(err, goraffe.ErrCycle) {
+//	    // report err.(*goraffe.CycleError).Cycle
+//	}
+func (g *Graph) TopologicalSort() ([]*Node, error) {
+	if !g.directed {
+		return nil, fmt.Errorf("could not sort graph topologically: %w", ErrUndirectedGraph)
+	}
+
+	inDegree := make([]int, len(g.nodeOrder))
+	ready := &indexHeap{}
+	for i, n := range g.nodeOrder {
+		inDegree[i] = g.InDegree(n.ID())
+		if inDegree[i] == 0 {
+			heap.Push(ready, i)
+		}
+	}
+
+	sorted := make([]*Node, 0, len(g.nodeOrder))
+	for ready.Len() > 0 {
+		i, _ := heap.Pop(ready).(int)
+		n := g.nodeOrder[i]
+		sorted = append(sorted, n)
+
+		for _, e := range g.outEdges[n.ID()] {
+			next := g.nodes[e.to.ID()]
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				heap.Push(ready, next)
+			}
+		}
+	}
+
+	if len(sorted) < len(g.nodeOrder) {
+		return nil, &CycleError{Cycle: g.FindCycles()[0]}
+	}
+
+	return sorted, nil
+}
+
+// FindCycles returns the cycles discovered by a depth-first traversal of the graph.
+// Each cycle lists its nodes in traversal order, and the last node has an edge back to
+// the first; a self-loop is reported as a single-node cycle. Every cycle in the graph
+// shares at least one edge with a reported cycle, so an empty result means the graph
+// is acyclic. Directed graphs follow edge direction; undirected graphs report cycles
+// formed by distinct edges, including parallel edges between the same pair of nodes.
+// The traversal visits nodes and edges in insertion order, so results are stable.
+func (g *Graph) FindCycles() [][]*Node {
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+
+	state := make(map[string]int, len(g.nodeOrder))
+	stackPos := make(map[string]int)
+	stack := make([]*Node, 0)
+	cycles := make([][]*Node, 0)
+
+	var visit func(n *Node, via *Edge)
+	visit = func(n *Node, via *Edge) {
+		state[n.ID()] = onStack
+		stackPos[n.ID()] = len(stack)
+		stack = append(stack, n)
+
+		for _, e := range g.traversalEdges(n.ID()) {
+			// Walking back along the edge we arrived by is not a cycle
+			if !g.directed && e == via {
+				continue
+			}
+
+			next := g.GetNode(e.otherEnd(n.ID()))
+			switch state[next.ID()] {
+			case unvisited:
+				visit(next, e)
+			case onStack:
+				cycles = append(cycles, slices.Clone(stack[stackPos[next.ID()]:]))
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[n.ID()] = done
+	}
+
+	for _, n := range g.nodeOrder {
+		if state[n.ID()] == unvisited {
+			visit(n, nil)
+		}
+	}
+
+	return cycles
+}
+
+// SCCOption configures StronglyConnectedComponents.
+type SCCOption interface {
+	applySCC(*sccConfig)
+}
+
+// sccConfig holds configuration for StronglyConnectedComponents.
+type sccConfig struct {
+	cluster bool
+}
+
+type sccOptionFunc func(*sccConfig)
+
+func (f sccOptionFunc) applySCC(cfg *sccConfig) {
+	f(cfg)
+}
+
+// WithSCCClusters wraps each non-trivial strongly connected component in a new cluster
+// subgraph named "cluster_scc_0", "cluster_scc_1", and so on, so that cycles stand out
+// when the graph is rendered. A component is non-trivial if it has more than one node
+// or a self-loop.
+func WithSCCClusters() SCCOption {
+	return sccOptionFunc(func(cfg *sccConfig) {
+		cfg.cluster = true
+	})
+}
+
+// StronglyConnectedComponents partitions the graph's nodes into strongly connected
+// components using Tarjan's algorithm. Nodes within a component follow the insertion
+// order of Nodes(), and components are ordered by their first node. In undirected
+// graphs every edge can be walked both ways, so the components are the connected ones.
+//
+// Example:
+//
+//	sccs := g.StronglyConnectedComponents(goraffe.WithSCCClusters())
+func (g *Graph) StronglyConnectedComponents(opts ...SCCOption) [][]*Node {
+	config := &sccConfig{}
+	for _, opt := range opts {
+		opt.applySCC(config)
+	}
+
+	components := g.tarjan()
+
+	byInsertion := func(a, b *Node) int {
+		return g.nodes[a.ID()] - g.nodes[b.ID()]
+	}
+	for _, component := range components {
+		slices.SortFunc(component, byInsertion)
+	}
+	slices.SortFunc(components, func(a, b []*Node) int {
+		return byInsertion(a[0], b[0])
+	})
+
+	if config.cluster {
+		g.clusterComponents(components)
+	}
+
+	return components
+}
+
+// tarjan returns the strongly connected components found by Tarjan's algorithm,
+// in the order the algorithm completes them.
+func (g *Graph) tarjan() [][]*Node {
+	index := make(map[string]int, len(g.nodeOrder))
+	lowLink := make(map[string]int, len(g.nodeOrder))
+	onStack := make(map[string]bool)
+	stack := make([]*Node, 0)
+	components := make([][]*Node, 0)
+
+	var connect func(n *Node)
+	connect = func(n *Node) {
+		id := n.ID()
+		index[id] = len(index)
+		lowLink[id] = index[id]
+		stack = append(stack, n)
+		onStack[id] = true
+
+		for _, e := range g.traversalEdges(id) {
+			next := g.GetNode(e.otherEnd(id))
+			if _, visited := index[next.ID()]; !visited {
+				connect(next)
+				lowLink[id] = min(lowLink[id], lowLink[next.ID()])
+			} else if onStack[next.ID()] {
+				lowLink[id] = min(lowLink[id], index[next.ID()])
+			}
+		}
+
+		// n is the root of a component: pop it off the stack
+		if lowLink[id] == index[id] {
+			component := make([]*Node, 0)
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top.ID()] = false
+				component = append(component, top)
+				if top.ID() == id {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for _, n := range g.nodeOrder {
+		if _, visited := index[n.ID()]; !visited {
+			connect(n)
+		}
+	}
+
+	return components
+}
+
+// clusterComponents wraps each non-trivial component in a new cluster subgraph.
+func (g *Graph) clusterComponents(components [][]*Node) {
+	count := 0
+	for _, component := range components {
+		if len(component) == 1 && len(g.EdgesBetween(component[0].ID(), component[0].ID())) == 0 {
+			continue
+		}
+
+		g.Subgraph(fmt.Sprintf("cluster_scc_%d", count), func(s *Subgraph) {
+			for _, n := range component {
+				_ = s.AddNode(n) // Safe to ignore - nodes come from the graph
+			}
+		})
+		count++
+	}
+}
+
+// traversalEdges returns the edges that can be walked from the node with the given ID:
+// its outgoing edges in directed graphs, and all of its edges in undirected graphs.
+func (g *Graph) traversalEdges(id string) []*Edge {
+	if g.directed {
+		return g.outEdges[id]
+	}
+
+	edges := slices.Clone(g.outEdges[id])
+	for _, e := range g.inEdges[id] {
+		// Self-loops were already collected as outgoing edges
+		if e.from.ID() != id {
+			edges = append(edges, e)
+		}
+	}
+
+	return edges
+}
+
+// otherEnd returns the ID of the endpoint of e opposite the node with the given ID.
+func (e *Edge) otherEnd(id string) string {
+	if e.from.ID() == id {
+		return e.to.ID()
+	}
+
+	return e.from.ID()
+}
+
+// indexHeap is a min-heap of node insertion indices, used to break ties by insertion order.
+type indexHeap []int
+
+func (h indexHeap) Len() int           { return len(h) }
+func (h indexHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h indexHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *indexHeap) Push(x any) {
+	i, _ := x.(int)
+	*h = append(*h, i)
+}
+
+func (h *indexHeap) Pop() any {
+	old := *h
+	i := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return i
+}
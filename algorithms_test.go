@@ -0,0 +1,202 @@
+// ABOUTME: Tests for graph algorithms: topological sort, cycle detection and SCCs.
+// ABOUTME: Verifies ordering stability, cycle reporting and optional SCC clustering.
+package goraffe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// buildDirected creates a directed graph from "from->to" pairs, adding nodes in first-mention order.
+func buildDirected(pairs ...[2]string) *Graph {
+	g := NewGraph(Directed)
+	for _, p := range pairs {
+		from := g.GetNode(p[0])
+		if from == nil {
+			from = NewNode(p[0])
+		}
+		to := g.GetNode(p[1])
+		if to == nil {
+			to = NewNode(p[1])
+		}
+		_, _ = g.AddEdge(from, to)
+	}
+	return g
+}
+
+func TestGraph_TopologicalSort(t *testing.T) {
+	t.Run("orders nodes along edges", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := buildDirected([2]string{"build", "test"}, [2]string{"fetch", "build"}, [2]string{"test", "deploy"})
+
+		order, err := g.TopologicalSort()
+
+		asrt.NoError(err, "expected acyclic graph to sort")
+		asrt.Equal([]string{"fetch", "build", "test", "deploy"}, nodeIDs(order))
+	})
+
+	t.Run("breaks ties by insertion order", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		for _, id := range []string{"C", "A", "B"} {
+			_ = g.AddNode(NewNode(id))
+		}
+		_, _ = g.AddEdge(g.GetNode("B"), g.GetNode("A"))
+
+		order, err := g.TopologicalSort()
+
+		asrt.NoError(err)
+		asrt.Equal([]string{"C", "B", "A"}, nodeIDs(order), "expected independent nodes in insertion order")
+	})
+
+	t.Run("reports cycles", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := buildDirected([2]string{"A", "B"}, [2]string{"B", "C"}, [2]string{"C", "A"}, [2]string{"C", "D"})
+
+		order, err := g.TopologicalSort()
+
+		asrt.Nil(order, "expected no ordering for cyclic graph")
+		asrt.ErrorIs(err, ErrCycle, "expected ErrCycle sentinel error")
+		var cycleErr *CycleError
+		asrt.True(errors.As(err, &cycleErr), "expected a *CycleError")
+		asrt.Equal([]string{"A", "B", "C"}, nodeIDs(cycleErr.Cycle), "expected the offending path")
+		asrt.Contains(err.Error(), "A -> B -> C -> A", "expected the cycle in the message")
+	})
+
+	t.Run("self-loop is a cycle", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := buildDirected([2]string{"A", "A"})
+
+		_, err := g.TopologicalSort()
+
+		asrt.ErrorIs(err, ErrCycle, "expected self-loop to be reported as a cycle")
+	})
+
+	t.Run("rejects undirected graphs", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Undirected)
+		_, _ = g.AddEdge(NewNode("A"), NewNode("B"))
+
+		_, err := g.TopologicalSort()
+
+		asrt.ErrorIs(err, ErrUndirectedGraph, "expected ErrUndirectedGraph sentinel error")
+	})
+}
+
+func TestGraph_FindCycles(t *testing.T) {
+	t.Run("acyclic directed graph", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := buildDirected([2]string{"A", "B"}, [2]string{"A", "C"}, [2]string{"B", "C"})
+
+		asrt.Empty(g.FindCycles(), "expected no cycles in a DAG")
+	})
+
+	t.Run("directed cycles and self-loops", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := buildDirected(
+			[2]string{"A", "B"}, [2]string{"B", "A"},
+			[2]string{"C", "D"}, [2]string{"D", "E"}, [2]string{"E", "C"},
+			[2]string{"F", "F"},
+		)
+
+		cycles := g.FindCycles()
+
+		asrt.Len(cycles, 3, "expected one cycle per back edge")
+		asrt.Equal([]string{"A", "B"}, nodeIDs(cycles[0]))
+		asrt.Equal([]string{"C", "D", "E"}, nodeIDs(cycles[1]))
+		asrt.Equal([]string{"F"}, nodeIDs(cycles[2]))
+	})
+
+	t.Run("undirected tree has no cycles", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Undirected)
+		a, b, c := NewNode("A"), NewNode("B"), NewNode("C")
+		_, _ = g.AddEdge(a, b)
+		_, _ = g.AddEdge(c, a)
+
+		asrt.Empty(g.FindCycles(), "expected walking an edge back not to count as a cycle")
+	})
+
+	t.Run("undirected cycle and parallel edges", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Undirected)
+		a, b, c, d := NewNode("A"), NewNode("B"), NewNode("C"), NewNode("D")
+		_, _ = g.AddEdge(a, b)
+		_, _ = g.AddEdge(b, c)
+		_, _ = g.AddEdge(c, a)
+		_, _ = g.AddEdge(c, d)
+		_, _ = g.AddEdge(d, c)
+
+		cycles := g.FindCycles()
+
+		asrt.Len(cycles, 2, "expected the triangle and the parallel edge pair")
+		asrt.Equal([]string{"A", "B", "C"}, nodeIDs(cycles[0]))
+		asrt.Equal([]string{"C", "D"}, nodeIDs(cycles[1]))
+	})
+}
+
+func TestGraph_StronglyConnectedComponents(t *testing.T) {
+	t.Run("partitions nodes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := buildDirected(
+			[2]string{"A", "B"}, [2]string{"B", "C"}, [2]string{"C", "A"},
+			[2]string{"C", "D"}, [2]string{"D", "E"}, [2]string{"E", "D"},
+			[2]string{"E", "F"},
+		)
+
+		sccs := g.StronglyConnectedComponents()
+
+		asrt.Len(sccs, 3)
+		asrt.Equal([]string{"A", "B", "C"}, nodeIDs(sccs[0]))
+		asrt.Equal([]string{"D", "E"}, nodeIDs(sccs[1]))
+		asrt.Equal([]string{"F"}, nodeIDs(sccs[2]))
+		asrt.Empty(g.Subgraphs(), "expected no clusters without the option")
+	})
+
+	t.Run("undirected graph yields connected components", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Undirected)
+		_, _ = g.AddEdge(NewNode("A"), NewNode("B"))
+		_, _ = g.AddEdge(NewNode("C"), NewNode("B"))
+		_ = g.AddNode(NewNode("D"))
+
+		sccs := g.StronglyConnectedComponents()
+
+		asrt.Len(sccs, 2)
+		asrt.Equal([]string{"A", "B", "C"}, nodeIDs(sccs[0]))
+		asrt.Equal([]string{"D"}, nodeIDs(sccs[1]))
+	})
+
+	t.Run("wraps non-trivial components in clusters", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := buildDirected(
+			[2]string{"A", "B"}, [2]string{"B", "A"},
+			[2]string{"B", "C"},
+			[2]string{"D", "D"},
+		)
+
+		g.StronglyConnectedComponents(WithSCCClusters())
+
+		subgraphs := g.Subgraphs()
+		asrt.Len(subgraphs, 2, "expected clusters for {A,B} and the self-loop on D")
+		asrt.Equal("cluster_scc_0", subgraphs[0].Name())
+		asrt.True(subgraphs[0].IsCluster())
+		asrt.Len(subgraphs[0].Nodes(), 2)
+		asrt.Equal("cluster_scc_1", subgraphs[1].Name())
+		asrt.Equal("D", subgraphs[1].Nodes()[0].ID())
+	})
+}
@@ -8,6 +8,7 @@ package goraffe
 type Content interface {
 	contentMarker()
 	toHTML() string
+	cloneContent() Content
 }
 
 // TextContent represents text with optional formatting (bold, italic, underline, subscript, superscript).
@@ -27,6 +28,11 @@ func Text(text string) *TextContent {
 	}
 }
 
+// Text returns the text without its formatting.
+func (t *TextContent) Text() string {
+	return t.text
+}
+
 // Bold sets the text to be bold and returns the TextContent for chaining.
 func (t *TextContent) Bold() *TextContent {
 	t.bold = true
@@ -59,6 +65,11 @@ func (t *TextContent) Sup() *TextContent {
 
 func (t *TextContent) contentMarker() {}
 
+func (t *TextContent) cloneContent() Content {
+	copied := *t
+	return &copied
+}
+
 func (t *TextContent) toHTML() string {
 	result := t.text
 
@@ -92,6 +103,10 @@ func BR() *LineBreak {
 
 func (l *LineBreak) contentMarker() {}
 
+func (l *LineBreak) cloneContent() Content {
+	return &LineBreak{}
+}
+
 func (l *LineBreak) toHTML() string {
 	return "<br/>"
 }
@@ -106,6 +121,10 @@ func HR() *HorizontalRule {
 
 func (h *HorizontalRule) contentMarker() {}
 
+func (h *HorizontalRule) cloneContent() Content {
+	return &HorizontalRule{}
+}
+
 func (h *HorizontalRule) toHTML() string {
 	return "<hr/>"
 }
@@ -0,0 +1,271 @@
+// ABOUTME: Implements deep copying of graphs and merging one graph into another.
+// ABOUTME: Clones share nothing with their source, including label trees and ports.
+package goraffe
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrIncompatibleGraphs is returned when merging a directed graph with an undirected one.
+var ErrIncompatibleGraphs = errors.New("cannot merge directed and undirected graphs")
+
+// ErrNodeConflict is returned by Merge with MergeFail when both graphs contain a node
+// with the same ID.
+var ErrNodeConflict = errors.New("node already exists in graph")
+
+// MergePolicy controls how Graph.Merge resolves nodes that exist in both graphs.
+type MergePolicy int
+
+const (
+	// MergeKeepExisting keeps the receiving graph's node and ignores the incoming one.
+	MergeKeepExisting MergePolicy = iota
+	// MergeReplace replaces the receiving graph's node with the incoming one,
+	// keeping its position in the node order. The receiving graph's edges and
+	// subgraphs are repointed at the incoming node.
+	MergeReplace
+	// MergeAttributes copies the incoming node's attributes onto the existing node,
+	// with incoming values taking precedence.
+	MergeAttributes
+	// MergeFail rejects the merge with ErrNodeConflict, leaving the graph unchanged.
+	MergeFail
+)
+
+// Clone returns a deep copy of the graph. Nodes, edges, attributes (including custom
+// attributes), HTML and record labels, ports, comments and subgraphs are all copied, and edge
+// ports are rewired to the copied labels, so the clone can be modified freely without
+// affecting the original.
+//
+// Example:
+//
+//	variant := g.Clone()
+//	variant.RemoveNode("legacy")
+func (g *Graph) Clone() *Graph {
+	clone := NewGraph()
+	clone.name = g.name
+	clone.directed = g.directed
+	clone.strict = g.strict
+	clone.comment = g.comment
+	clone.attrs = g.attrs.clone()
+	clone.defaultNodeAttrs = g.defaultNodeAttrs.clone()
+	clone.defaultEdgeAttrs = g.defaultEdgeAttrs.clone()
+
+	for _, n := range g.nodeOrder {
+		_ = clone.AddNode(n.clone()) // Safe to ignore - clones are never nil
+	}
+
+	edges := make(map[*Edge]*Edge, g.edges.len())
+	for e := range g.edges.all() {
+		copied := e.clone()
+		copied.bind(clone)
+		clone.addEdge(copied)
+		edges[e] = copied
+	}
+
+	for _, sg := range g.subgraphs {
+		copied := sg.clone()
+		copied.bind(clone, edges)
+		clone.subgraphs = append(clone.subgraphs, copied)
+	}
+
+	return clone
+}
+
+// Merge adds a copy of every node, edge and subgraph of other to g. Nodes whose ID
+// already exists in g are resolved according to policy; edges and subgraphs of other
+// are attached to the resulting nodes and appended after g's own, and strict graphs
+// merge duplicate edges as AddEdge does. g keeps its own name, attributes and defaults,
+// and other is never modified.
+// Returns ErrIncompatibleGraphs if only one of the graphs is directed, and
+// ErrNodeConflict if policy is MergeFail and the graphs share a node ID.
+//
+// Example:
+//
+//	err := g.Merge(other, goraffe.MergeAttributes)
+func (g *Graph) Merge(other *Graph, policy MergePolicy) error {
+	if g.directed != other.directed {
+		return fmt.Errorf("could not merge graphs: %w", ErrIncompatibleGraphs)
+	}
+
+	if policy == MergeFail {
+		for _, n := range other.nodeOrder {
+			if g.GetNode(n.ID()) != nil {
+				return fmt.Errorf("could not merge node %q: %w", n.ID(), ErrNodeConflict)
+			}
+		}
+	}
+
+	incoming := other.Clone()
+
+	for _, n := range incoming.nodeOrder {
+		existing := g.GetNode(n.ID())
+		switch {
+		case existing == nil:
+			_ = g.AddNode(n) // Safe to ignore - clones are never nil
+		case policy == MergeReplace:
+			g.replaceNode(n)
+		case policy == MergeAttributes:
+			existing.attrs.merge(n.attrs)
+		}
+	}
+
+	edges := make(map[*Edge]*Edge, incoming.edges.len())
+	for e := range incoming.edges.all() {
+		e.bind(g)
+		merged, err := g.insertEdge(e)
+		if err != nil {
+			return err
+		}
+		edges[e] = merged
+	}
+
+	for _, sg := range incoming.subgraphs {
+		sg.bind(g, edges)
+		g.subgraphs = append(g.subgraphs, sg)
+	}
+
+	return nil
+}
+
+// replaceNode swaps the node with n's ID for n, keeping its position, and points g's
+// edges and subgraphs at n in its place. Edge ports are rewired to n's label.
+func (g *Graph) replaceNode(n *Node) {
+	_ = g.AddNode(n) // Safe to ignore - clones are never nil
+
+	for _, e := range g.outEdges[n.ID()] {
+		e.bind(g)
+	}
+	for _, e := range g.inEdges[n.ID()] {
+		e.bind(g)
+	}
+
+	for _, sg := range g.subgraphs {
+		sg.replaceNode(n)
+	}
+}
+
+// replaceNode swaps the member with n's ID for n in this subgraph and all nested
+// subgraphs, keeping its position. Subgraphs without such a member are left unchanged.
+func (sg *Subgraph) replaceNode(n *Node) {
+	if idx, exists := sg.nodes[n.ID()]; exists {
+		sg.nodeOrder[idx] = n
+	}
+
+	for _, nested := range sg.subgraphs {
+		nested.replaceNode(n)
+	}
+}
+
+// clone returns a deep copy of the node.
+func (n *Node) clone() *Node {
+	return &Node{
+		id:      n.id,
+		attrs:   n.attrs.clone(),
+		comment: n.comment,
+	}
+}
+
+// clone returns a copy of the edge with its own attributes. The copy still refers to
+// the original endpoints and ports until it is bound to a graph.
+func (e *Edge) clone() *Edge {
+	return &Edge{
+		from:    e.from,
+		to:      e.to,
+		attrs:   e.attrs.clone(),
+		comment: e.comment,
+	}
+}
+
+// bind points the edge at g's nodes with the same IDs as its endpoints, and rewires
+// its ports to the matching ports in those nodes' labels.
+func (e *Edge) bind(g *Graph) {
+	e.from = g.GetNode(e.from.ID())
+	e.to = g.GetNode(e.to.ID())
+	e.attrs.fromPort = rebindPort(e.attrs.fromPort, e.from)
+	e.attrs.toPort = rebindPort(e.attrs.toPort, e.to)
+}
+
+// rebindPort returns the port of n's label with the same ID as p, or a copy of p
+// if the label does not define it. The result keeps p's compass point.
+func rebindPort(p *Port, n *Node) *Port {
+	if p == nil {
+		return nil
+	}
+
+	if labelPort := n.attrs.port(p.id); labelPort != nil {
+		if p.compass != "" {
+			return labelPort.WithCompass(p.compass)
+		}
+		return labelPort
+	}
+
+	copied := *p
+	return &copied
+}
+
+// clone returns a copy of the subgraph hierarchy with its own attributes. The copy
+// still refers to the original nodes and edges until it is bound to a graph.
+func (sg *Subgraph) clone() *Subgraph {
+	copied := &Subgraph{
+		name:      sg.name,
+		nodes:     make(map[string]int, len(sg.nodes)),
+		nodeOrder: slices.Clone(sg.nodeOrder),
+		parent:    sg.parent,
+		subgraphs: make([]*Subgraph, 0, len(sg.subgraphs)),
+		comment:   sg.comment,
+	}
+
+	for id, idx := range sg.nodes {
+		copied.nodes[id] = idx
+	}
+
+	for e := range sg.edges.all() {
+		copied.edges.add(e)
+	}
+
+	sg.cloneAttrsInto(copied)
+
+	for _, nested := range sg.subgraphs {
+		copied.subgraphs = append(copied.subgraphs, nested.clone())
+	}
+
+	return copied
+}
+
+// bind attaches the subgraph hierarchy to g, pointing it at g's nodes with the same
+// IDs and replacing each edge with its counterpart in edges. Edges without a
+// counterpart are dropped.
+func (sg *Subgraph) bind(g *Graph, edges map[*Edge]*Edge) {
+	sg.parent = g
+
+	for i, n := range sg.nodeOrder {
+		sg.nodeOrder[i] = g.GetNode(n.ID())
+	}
+
+	var bound edgeList
+	for e := range sg.edges.all() {
+		if counterpart := edges[e]; counterpart != nil && !bound.contains(counterpart) {
+			bound.add(counterpart)
+		}
+	}
+	sg.edges = bound
+
+	for _, nested := range sg.subgraphs {
+		nested.bind(g, edges)
+	}
+}
+
+// cloneAttrsInto gives dst its own copies of the subgraph's attributes and default
+// node and edge attributes.
+func (sg *Subgraph) cloneAttrsInto(dst *Subgraph) {
+	if sg.attrs != nil {
+		dst.attrs = sg.attrs.clone()
+	}
+	if sg.defaultNodeAttrs != nil {
+		dst.defaultNodeAttrs = sg.defaultNodeAttrs.clone()
+	}
+	if sg.defaultEdgeAttrs != nil {
+		dst.defaultEdgeAttrs = sg.defaultEdgeAttrs.clone()
+	}
+}
@@ -0,0 +1,216 @@
+// ABOUTME: Tests for deep copying graphs and merging graphs together.
+// ABOUTME: Verifies clones are independent and merge policies resolve node conflicts.
+package goraffe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraph_Clone(t *testing.T) {
+	t.Run("copies structure and attributes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed, Strict, WithName("G"), WithGraphAttribute("ratio", "fill"),
+			WithDefaultNodeAttrs(WithBoxShape()))
+		var sg *Subgraph
+		g.Subgraph("cluster_0", func(s *Subgraph) {
+			sg = s
+			s.SetLabel("group")
+			s.SetDefaultNodeAttrs(WithColor("blue"))
+			_, _ = s.AddEdge(NewNode("A", WithNodeAttribute("tooltip", "a")), NewNode("B"), WithEdgeColor("red"))
+		})
+
+		clone := g.Clone()
+
+		asrt.Equal(g.String(), clone.String(), "expected identical DOT output")
+		asrt.True(clone.IsStrict())
+		asrt.NotSame(g.GetNode("A"), clone.GetNode("A"), "expected nodes to be copied")
+		asrt.NotSame(g.Edges()[0], clone.Edges()[0], "expected edges to be copied")
+		asrt.Same(clone.GetNode("A"), clone.Edges()[0].From(), "expected edges to use the cloned nodes")
+
+		cloneSg := clone.Subgraphs()[0]
+		asrt.NotSame(sg, cloneSg, "expected subgraphs to be copied")
+		asrt.NotSame(sg.DefaultNodeAttrs(), cloneSg.DefaultNodeAttrs(), "expected subgraph defaults to be copied")
+		asrt.Same(clone.Edges()[0], cloneSg.Edges()[0], "expected subgraph edges to use cloned edges")
+		asrt.Same(clone.GetNode("B"), cloneSg.Nodes()[1], "expected subgraph nodes to use cloned nodes")
+		asrt.Equal([]string{"B"}, nodeIDs(clone.Successors("A")), "expected adjacency index to be rebuilt")
+	})
+
+	t.Run("clone is independent of original", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		_, _ = g.AddEdge(NewNode("A", WithNodeAttribute("k", "v")), NewNode("B"))
+		g.Subgraph("cluster_0", func(s *Subgraph) { _ = s.AddNode(g.GetNode("A")) })
+		original := g.String()
+
+		clone := g.Clone()
+		clone.GetNode("A").Attrs().setCustom("k", "changed")
+		WithEdgeLabel("changed").applyEdge(clone.Edges()[0].Attrs())
+		clone.Subgraphs()[0].SetLabel("changed")
+		WithGraphLabel("changed").applyGraph(clone)
+		clone.RemoveNode("B")
+
+		asrt.Equal(original, g.String(), "expected original graph to be unaffected")
+	})
+
+	t.Run("rewires ports to copied labels", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		htmlCell := Cell(Text("out")).Port("out")
+		record := Record(Field("in").Port("in"), FieldGroup(Field("x").Port("x")))
+		a := NewNode("A", WithHTMLLabel(HTMLTable(Row(htmlCell))))
+		b := NewNode("B", WithRecordLabel(record))
+		g := NewGraph(Directed)
+		_, _ = g.AddEdge(a, b, FromPort(htmlCell.GetPort()), ToPort(b.Attrs().RecordLabel().findPort("x")))
+
+		clone := g.Clone()
+		e := clone.Edges()[0]
+
+		clonedHTMLPort := clone.GetNode("A").Attrs().HTMLLabel().findPort("out")
+		asrt.NotSame(htmlCell.GetPort(), clonedHTMLPort, "expected HTML label ports to be copied")
+		asrt.Same(clonedHTMLPort, e.Attrs().FromPort(), "expected from port to point into the cloned label")
+		asrt.Equal("A", clonedHTMLPort.NodeID())
+
+		clonedRecordPort := clone.GetNode("B").Attrs().RecordLabel().findPort("x")
+		asrt.NotSame(record.findPort("x"), clonedRecordPort, "expected record label ports to be copied")
+		asrt.Same(clonedRecordPort, e.Attrs().ToPort(), "expected to port to point into the cloned label")
+		asrt.Equal(g.String(), clone.String())
+	})
+
+	t.Run("keeps compass points on rewired ports", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		htmlCell := Cell(Text("out")).Port("out")
+		a := NewNode("A", WithHTMLLabel(HTMLTable(Row(htmlCell))))
+		b := NewNode("B")
+		g := NewGraph(Directed)
+		_, _ = g.AddEdge(a, b, FromPort(htmlCell.GetPort().WithCompass(CompassS)), ToCompass(b, CompassN))
+
+		clone := g.Clone()
+		e := clone.Edges()[0]
+
+		asrt.Equal("out", e.Attrs().FromPort().ID())
+		asrt.Equal(CompassS, e.Attrs().FromPort().Compass())
+		asrt.Equal(CompassN, e.Attrs().ToPort().Compass())
+		asrt.Equal(g.String(), clone.String())
+	})
+}
+
+func TestGraph_Merge(t *testing.T) {
+	newPair := func() (*Graph, *Graph) {
+		g := NewGraph(Directed)
+		_, _ = g.AddEdge(NewNode("A", WithLabel("mine"), WithColor("red")), NewNode("B"))
+
+		other := NewGraph(Directed)
+		_, _ = other.AddEdge(NewNode("A", WithLabel("theirs")), NewNode("C"))
+		other.Subgraph("cluster_other", func(s *Subgraph) {
+			_ = s.AddNode(other.GetNode("C"))
+		})
+		return g, other
+	}
+
+	t.Run("keep existing", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g, other := newPair()
+		err := g.Merge(other, MergeKeepExisting)
+
+		asrt.NoError(err)
+		asrt.Equal([]string{"A", "B", "C"}, nodeIDs(g.Nodes()))
+		asrt.Equal("mine", g.GetNode("A").Attrs().Label())
+		asrt.Equal([]string{"A->B", "A->C"}, edgePairs(g.Edges()))
+		asrt.Same(g.GetNode("A"), g.Edges()[1].From(), "expected merged edges to use the kept node")
+		asrt.Len(g.Subgraphs(), 1)
+		asrt.Same(g.GetNode("C"), g.Subgraphs()[0].Nodes()[0])
+		asrt.NotSame(other.GetNode("C"), g.GetNode("C"), "expected incoming nodes to be copies")
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g, other := newPair()
+		err := g.Merge(other, MergeReplace)
+
+		asrt.NoError(err)
+		asrt.Equal("theirs", g.GetNode("A").Attrs().Label())
+		asrt.Equal("", g.GetNode("A").Attrs().Color())
+		asrt.Equal("A", g.Nodes()[0].ID(), "expected replaced node to keep its position")
+	})
+
+	t.Run("replace repoints edges, subgraphs and ports", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		oldRecord := Record(Field("out").Port("out"))
+		g.Subgraph("cluster_x", func(s *Subgraph) {
+			_ = s.AddNode(NewNode("A", WithColor("red"), WithRecordLabel(oldRecord)))
+		})
+		_, _ = g.AddEdge(g.GetNode("A"), NewNode("B"), FromPort(oldRecord.findPort("out")))
+
+		other := NewGraph(Directed)
+		newRecord := Record(Field("out").Port("out"))
+		_ = other.AddNode(NewNode("A", WithColor("blue"), WithRecordLabel(newRecord)))
+
+		err := g.Merge(other, MergeReplace)
+
+		asrt.NoError(err)
+		replaced := g.GetNode("A")
+		asrt.Equal("blue", replaced.Attrs().Color())
+		asrt.Same(replaced, g.Edges()[0].From(), "expected edges to use the replacing node")
+		asrt.Same(replaced, g.Subgraphs()[0].Nodes()[0], "expected subgraphs to use the replacing node")
+		asrt.Same(replaced.Attrs().RecordLabel().findPort("out"), g.Edges()[0].Attrs().FromPort(),
+			"expected edge ports to point into the replacing node's label")
+		asrt.Same(replaced, g.Predecessors("B")[0])
+		asrt.NotContains(g.String(), "red")
+	})
+
+	t.Run("merge attributes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g, other := newPair()
+		err := g.Merge(other, MergeAttributes)
+
+		asrt.NoError(err)
+		asrt.Equal("theirs", g.GetNode("A").Attrs().Label())
+		asrt.Equal("red", g.GetNode("A").Attrs().Color())
+	})
+
+	t.Run("fail on conflict", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g, other := newPair()
+		before := g.String()
+		err := g.Merge(other, MergeFail)
+
+		asrt.ErrorIs(err, ErrNodeConflict)
+		asrt.Equal(before, g.String(), "expected graph to be unchanged")
+	})
+
+	t.Run("strict graphs merge duplicate edges", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed, Strict)
+		_, _ = g.AddEdge(NewNode("A"), NewNode("B"), WithEdgeColor("red"))
+		other := NewGraph(Directed)
+		_, _ = other.AddEdge(NewNode("A"), NewNode("B"), WithEdgeLabel("x"))
+		_, _ = other.AddEdge(NewNode("A"), NewNode("A"))
+
+		err := g.Merge(other, MergeKeepExisting)
+
+		asrt.NoError(err)
+		asrt.Len(g.Edges(), 1)
+		asrt.Equal("red", g.Edges()[0].Attrs().Color())
+		asrt.Equal("x", g.Edges()[0].Attrs().Label())
+	})
+
+	t.Run("rejects mixed graph types", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		err := NewGraph(Directed).Merge(NewGraph(Undirected), MergeKeepExisting)
+
+		asrt.ErrorIs(err, ErrIncompatibleGraphs)
+	})
+}
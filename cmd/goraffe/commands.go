@@ -0,0 +1,184 @@
+// ABOUTME: Implements the goraffe subcommands: fmt, lint, render, convert and stats.
+// ABOUTME: Each command parses its own flags and reads from files or stdin.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/mikowitz/goraffe"
+)
+
+// errLint reports that at least one file failed to lint. Each failure has already been written.
+var errLint = errors.New("found invalid DOT")
+
+// runFmt reformats DOT source with goraffe.FormatDOT, writing to stdout or, with -w,
+// back to each source file.
+func runFmt(e *env, args []string) error {
+	fs := e.flagSet()
+	write := fs.Bool("w", false, "write the result to the source file instead of stdout")
+	indent := fs.String("indent", "\t", "indentation for each nesting level")
+	attrPerLine := fs.Bool("attr-per-line", false, "write each attribute on its own line")
+	paths, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	opts := []goraffe.PrinterOption{goraffe.WithIndent(*indent)}
+	if *attrPerLine {
+		opts = append(opts, goraffe.WithAttributePerLine())
+	}
+
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	for _, path := range paths {
+		if *write && isStdio(path) {
+			return e.usageError(fs, "-w cannot be used with stdin")
+		}
+		if err := e.formatFile(path, *write, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatFile formats a single file, replacing its contents if overwrite is set.
+func (e *env) formatFile(path string, overwrite bool, opts []goraffe.PrinterOption) error {
+	src, err := e.readInput(path)
+	if err != nil {
+		return err
+	}
+
+	out, err := goraffe.FormatDOT(src, opts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", displayName(path), err)
+	}
+
+	if !overwrite {
+		_, err = e.stdout.Write(out)
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Clean(path), out, info.Mode().Perm())
+}
+
+// runLint parses each file and reports every one that is not valid DOT.
+func runLint(e *env, args []string) error {
+	fs := e.flagSet()
+	paths, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	failed := false
+	for _, path := range paths {
+		if _, err := e.parseInput(path); err != nil {
+			_, _ = fmt.Fprintln(e.stderr, err)
+			failed = true
+		}
+	}
+
+	if failed {
+		return errLint
+	}
+	return nil
+}
+
+// runRender renders a graph with Graphviz, to the file named by -o or to stdout.
+func runRender(e *env, args []string) error {
+	fs := e.flagSet()
+	format := fs.String("T", string(goraffe.SVG), "output `format`, such as svg, png or pdf")
+	layout := fs.String("K", string(goraffe.LayoutDot), "Graphviz `layout` engine, such as dot or neato")
+	output := fs.String("o", "", "output `file` (default stdout)")
+	paths, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(paths) > 1 {
+		return e.usageError(fs, "too many arguments")
+	}
+
+	g, err := e.parseInput(argAt(paths, 0))
+	if err != nil {
+		return err
+	}
+
+	opt := goraffe.WithLayout(goraffe.Layout(*layout))
+	if isStdio(*output) {
+		return g.Render(goraffe.Format(*format), e.stdout, opt)
+	}
+	return g.RenderToFile(goraffe.Format(*format), *output, opt)
+}
+
+// runConvert converts a graph to the format named by -T or by the output file's extension.
+// DOT output is regenerated by goraffe itself; other formats are rendered by Graphviz.
+func runConvert(e *env, args []string) error {
+	fs := e.flagSet()
+	formatFlag := fs.String("T", "", "output `format` (default inferred from the output file)")
+	layout := fs.String("K", string(goraffe.LayoutDot), "Graphviz `layout` engine for rendered formats")
+	paths, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(paths) > 2 {
+		return e.usageError(fs, "too many arguments")
+	}
+
+	input, output := argAt(paths, 0), argAt(paths, 1)
+	format := goraffe.Format(*formatFlag)
+	if format == "" && !isStdio(output) {
+		format = formatFromPath(output)
+	}
+	if format == "" {
+		return e.usageError(fs, "-T is required when the output file has no extension")
+	}
+
+	g, err := e.parseInput(input)
+	if err != nil {
+		return err
+	}
+
+	return e.writeOutput(output, func(w io.Writer) error {
+		if format == goraffe.DOT || format == "gv" {
+			if err := g.WriteDOT(w); err != nil {
+				return err
+			}
+			_, err := io.WriteString(w, "\n")
+			return err
+		}
+		return g.Render(format, w, goraffe.WithLayout(goraffe.Layout(*layout)))
+	})
+}
+
+// runStats prints the size of a graph and the spread of its node degrees.
+func runStats(e *env, args []string) error {
+	fs := e.flagSet()
+	paths, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(paths) > 1 {
+		return e.usageError(fs, "too many arguments")
+	}
+
+	g, err := e.parseInput(argAt(paths, 0))
+	if err != nil {
+		return err
+	}
+
+	writeStats(e.stdout, computeStats(g))
+	return nil
+}
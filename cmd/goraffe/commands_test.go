@@ -0,0 +1,192 @@
+// ABOUTME: Tests for the goraffe subcommands.
+// ABOUTME: Covers files and stdin/stdout for fmt, lint, render, convert and stats.
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requireGraphviz skips the test if the Graphviz dot binary is not available.
+func requireGraphviz(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("dot"); err != nil {
+		t.Skip("Graphviz not installed, skipping test")
+	}
+}
+
+func TestFmt(t *testing.T) {
+	t.Run("formats stdin to stdout", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		code, stdout, _ := runCommand("digraph{a->b // edge\n}", "fmt")
+
+		asrt.Equal(exitOK, code)
+		asrt.Equal("digraph {\n\ta -> b; // edge\n}\n", stdout)
+	})
+
+	t.Run("applies indent and attribute per line", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		code, stdout, _ := runCommand("graph{a[color=red]}", "fmt", "-indent", "  ", "-attr-per-line")
+
+		asrt.Equal(exitOK, code)
+		asrt.Equal("graph {\n  a [\n    color=red\n  ];\n}\n", stdout)
+	})
+
+	t.Run("rewrites files in place with -w", func(t *testing.T) {
+		req := require.New(t)
+
+		path := writeTempFile(t, "g.dot", "graph{a}")
+
+		code, stdout, _ := runCommand("", "fmt", "-w", path)
+
+		req.Equal(exitOK, code)
+		req.Empty(stdout)
+		data, err := os.ReadFile(path)
+		req.NoError(err)
+		req.Equal("graph {\n\ta;\n}\n", string(data))
+	})
+
+	t.Run("refuses -w with stdin", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		code, _, stderr := runCommand("graph{a}", "fmt", "-w")
+
+		asrt.Equal(exitUsage, code)
+		asrt.Contains(stderr, "-w cannot be used with stdin")
+	})
+}
+
+func TestLint(t *testing.T) {
+	t.Run("accepts valid files silently", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		code, stdout, stderr := runCommand("", "lint", writeTempFile(t, "ok.dot", "digraph { a -> b }"))
+
+		asrt.Equal(exitOK, code)
+		asrt.Empty(stdout)
+		asrt.Empty(stderr)
+	})
+
+	t.Run("reports every invalid file", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		good := writeTempFile(t, "good.dot", "digraph { a }")
+		bad := writeTempFile(t, "bad.dot", "digraph { a -> }")
+
+		code, _, stderr := runCommand("graph {", "lint", bad, good, "-")
+
+		asrt.Equal(exitError, code)
+		asrt.Contains(stderr, bad+": parse error at 1:16")
+		asrt.Contains(stderr, "<stdin>: parse error")
+		asrt.NotContains(stderr, good)
+	})
+}
+
+func TestRender(t *testing.T) {
+	t.Run("rejects extra arguments", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		code, _, stderr := runCommand("", "render", "a.dot", "b.dot")
+
+		asrt.Equal(exitUsage, code)
+		asrt.Contains(stderr, "too many arguments")
+	})
+
+	t.Run("renders stdin to stdout", func(t *testing.T) {
+		requireGraphviz(t)
+		asrt := assert.New(t)
+
+		code, stdout, _ := runCommand("digraph { a -> b }", "render", "-T", "svg", "-K", "neato")
+
+		asrt.Equal(exitOK, code)
+		asrt.Contains(stdout, "<svg")
+	})
+
+	t.Run("renders to the file named by -o", func(t *testing.T) {
+		requireGraphviz(t)
+		asrt := assert.New(t)
+
+		in := writeTempFile(t, "in.dot", "digraph { a -> b }")
+		out := filepath.Join(t.TempDir(), "out.svg")
+
+		code, _, _ := runCommand("", "render", in, "-T", "svg", "-o", out)
+
+		asrt.Equal(exitOK, code)
+		asrt.FileExists(out)
+	})
+}
+
+func TestConvert(t *testing.T) {
+	t.Run("writes DOT without Graphviz", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		code, stdout, _ := runCommand("digraph { a -> b }", "convert", "-T", "dot")
+
+		asrt.Equal(exitOK, code)
+		asrt.Equal("digraph {\n\t\"a\";\n\t\"b\";\n\t\"a\" -> \"b\";\n}\n", stdout)
+	})
+
+	t.Run("infers the format from the output file", func(t *testing.T) {
+		req := require.New(t)
+
+		in := writeTempFile(t, "in.dot", "graph { a -- b }")
+		out := filepath.Join(t.TempDir(), "out.gv")
+
+		code, _, _ := runCommand("", "convert", in, out)
+
+		req.Equal(exitOK, code)
+		data, err := os.ReadFile(out)
+		req.NoError(err)
+		req.Contains(string(data), "\"a\" -- \"b\";")
+	})
+
+	t.Run("requires a format when writing to stdout", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		code, _, stderr := runCommand("graph { a }", "convert")
+
+		asrt.Equal(exitUsage, code)
+		asrt.Contains(stderr, "-T is required")
+	})
+
+	t.Run("renders other formats with Graphviz", func(t *testing.T) {
+		requireGraphviz(t)
+		asrt := assert.New(t)
+
+		out := filepath.Join(t.TempDir(), "out.svg")
+
+		code, _, _ := runCommand("digraph { a -> b }", "convert", "-", out)
+
+		asrt.Equal(exitOK, code)
+		asrt.FileExists(out)
+	})
+}
+
+func TestStats(t *testing.T) {
+	t.Run("prints counts and degrees", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		code, stdout, _ := runCommand(`digraph G {
+			subgraph cluster_a { a; subgraph inner { b } }
+			a -> b; a -> c
+		}`, "stats")
+
+		asrt.Equal(exitOK, code)
+		asrt.Equal("name:       G\n"+
+			"type:       digraph\n"+
+			"nodes:      3\n"+
+			"edges:      2\n"+
+			"subgraphs:  2\n"+
+			"clusters:   1\n"+
+			"in-degree:  min 0, max 1 (b), mean 0.67\n"+
+			"out-degree: min 0, max 2 (a), mean 0.67\n"+
+			"degree:     min 1, max 2 (a), mean 1.33\n", stdout)
+	})
+}
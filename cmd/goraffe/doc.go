@@ -0,0 +1,24 @@
+// Goraffe is a command-line tool for working with Graphviz DOT files.
+//
+// Usage:
+//
+//	goraffe <command> [arguments]
+//
+// The commands are:
+//
+//	fmt      reformat DOT source, keeping comments and statement order
+//	lint     check that DOT files parse, reporting each error with its position
+//	render   render a graph with a Graphviz layout engine
+//	convert  convert a graph to the format named by -T or the output file's extension
+//	stats    print node, edge, subgraph and cluster counts and node degrees
+//
+// Files named - or omitted are read from stdin and written to stdout, so commands
+// can be chained:
+//
+//	goraffe fmt < in.dot | goraffe render -T svg -K neato > out.svg
+//
+// Flags may be given before or after file arguments. Run "goraffe <command> -h"
+// for the flags each command accepts. render always runs Graphviz, so render -T dot
+// writes Graphviz's laid-out DOT. convert writes DOT and gv output itself and requires
+// Graphviz only for other formats.
+package main
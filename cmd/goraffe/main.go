@@ -0,0 +1,214 @@
+// ABOUTME: Entry point for the goraffe command-line tool.
+// ABOUTME: Dispatches subcommands and provides the shared stdin/stdout handling they use.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mikowitz/goraffe"
+)
+
+// Exit codes returned by run.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// stdinName is how standard input is referred to in messages.
+const stdinName = "<stdin>"
+
+// command is a goraffe subcommand.
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(env *env, args []string) error
+}
+
+// commands lists the subcommands in the order they are shown in the usage message.
+var commands = []command{
+	{"fmt", "fmt [-w] [-indent s] [-attr-per-line] [file...]", "reformat DOT source", runFmt},
+	{"lint", "lint [file...]", "check that DOT files parse", runLint},
+	{"render", "render [-T format] [-K layout] [-o file] [file]", "render a graph with Graphviz", runRender},
+	{"convert", "convert [-T format] [-K layout] [in [out]]", "convert a graph to another format", runConvert},
+	{"stats", "stats [file]", "print node, edge, cluster and degree counts", runStats},
+}
+
+// errUsage reports invalid arguments. The message has already been written.
+var errUsage = errors.New("usage")
+
+// env holds the running command and the streams it reads from and writes to.
+type env struct {
+	cmd    command
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the subcommand named by args[0] and returns the process exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	e := &env{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		e.usage()
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+
+		e.cmd = cmd
+		err := cmd.run(e, args[1:])
+		switch {
+		case err == nil:
+			return exitOK
+		case errors.Is(err, errUsage):
+			return exitUsage
+		default:
+			_, _ = fmt.Fprintf(stderr, "goraffe %s: %v\n", cmd.name, err)
+			return exitError
+		}
+	}
+
+	_, _ = fmt.Fprintf(stderr, "goraffe: unknown command %q\n", args[0])
+	e.usage()
+	return exitUsage
+}
+
+// usage writes the list of subcommands to stderr.
+func (e *env) usage() {
+	_, _ = fmt.Fprintln(e.stderr, "Usage: goraffe <command> [arguments]")
+	_, _ = fmt.Fprintln(e.stderr, "\nCommands:")
+	for _, cmd := range commands {
+		_, _ = fmt.Fprintf(e.stderr, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	_, _ = fmt.Fprintln(e.stderr, "\nFiles named - or omitted are read from stdin and written to stdout.")
+}
+
+// flagSet returns a flag set for the running command that reports errors to stderr.
+func (e *env) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(e.cmd.name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(e.stderr, "Usage: goraffe %s\n", e.cmd.usage)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parseFlags parses args, allowing flags to follow positional arguments as in
+// "goraffe render in.dot -o out.svg". Returns the positional arguments, or errUsage
+// once the flag set has reported a bad flag.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, errUsage
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// argAt returns the i'th positional argument, or an empty string if there are fewer.
+func argAt(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+// usageError writes msg and the command's usage to stderr and returns errUsage.
+func (e *env) usageError(fs *flag.FlagSet, msg string) error {
+	_, _ = fmt.Fprintf(e.stderr, "goraffe %s: %s\n", fs.Name(), msg)
+	fs.Usage()
+	return errUsage
+}
+
+// isStdio reports whether path refers to standard input or output.
+func isStdio(path string) bool {
+	return path == "" || path == "-"
+}
+
+// displayName returns the name used for path in messages.
+func displayName(path string) string {
+	if isStdio(path) {
+		return stdinName
+	}
+	return path
+}
+
+// readInput reads the named file, or stdin if path is empty or "-".
+func (e *env) readInput(path string) ([]byte, error) {
+	if isStdio(path) {
+		return io.ReadAll(e.stdin)
+	}
+
+	return os.ReadFile(filepath.Clean(path))
+}
+
+// parseInput reads and parses the graph in the named file, or stdin if path is empty or "-".
+func (e *env) parseInput(path string) (*goraffe.Graph, error) {
+	src, err := e.readInput(path)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := goraffe.ParseString(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", displayName(path), err)
+	}
+
+	return g, nil
+}
+
+// writeOutput calls write with the named file, or stdout if path is empty or "-".
+// The file is removed again if write fails.
+func (e *env) writeOutput(path string, write func(io.Writer) error) error {
+	if isStdio(path) {
+		return write(e.stdout)
+	}
+
+	//nolint:gosec // G304: the output path is chosen by the user
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	writeErr := write(file)
+	closeErr := file.Close()
+	if writeErr != nil {
+		_ = os.Remove(path)
+		return writeErr
+	}
+
+	return closeErr
+}
+
+// formatFromPath infers an output format from a file extension, such as "svg" for "out.svg".
+// Returns an empty format if the path has no extension.
+func formatFromPath(path string) goraffe.Format {
+	return goraffe.Format(strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")))
+}
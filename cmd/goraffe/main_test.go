@@ -0,0 +1,123 @@
+// ABOUTME: Tests for goraffe command dispatch and the shared input/output helpers.
+// ABOUTME: Commands are run in-process through run with in-memory streams.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runCommand runs goraffe with the given arguments and stdin, returning the exit code and output.
+func runCommand(stdin string, args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = run(args, strings.NewReader(stdin), &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+// writeTempFile writes content to a file in a fresh temporary directory and returns its path.
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestRun_Dispatch(t *testing.T) {
+	t.Run("prints usage without a command", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		code, _, stderr := runCommand("")
+
+		asrt.Equal(exitUsage, code)
+		asrt.Contains(stderr, "Usage: goraffe <command>")
+		for _, cmd := range []string{"fmt", "lint", "render", "convert", "stats"} {
+			asrt.Contains(stderr, "  "+cmd+" ")
+		}
+	})
+
+	t.Run("help exits successfully", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		code, _, stderr := runCommand("", "help")
+
+		asrt.Equal(exitOK, code)
+		asrt.Contains(stderr, "Commands:")
+	})
+
+	t.Run("rejects unknown commands", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		code, _, stderr := runCommand("", "draw")
+
+		asrt.Equal(exitUsage, code)
+		asrt.Contains(stderr, `unknown command "draw"`)
+	})
+
+	t.Run("reports command errors with the command name", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		code, _, stderr := runCommand("digraph {", "stats")
+
+		asrt.Equal(exitError, code)
+		asrt.Contains(stderr, "goraffe stats: <stdin>: parse error")
+	})
+
+	t.Run("bad flags print the command usage", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		code, _, stderr := runCommand("", "fmt", "-x")
+
+		asrt.Equal(exitUsage, code)
+		asrt.Contains(stderr, "Usage: goraffe fmt [-w]")
+	})
+}
+
+func TestParseFlags(t *testing.T) {
+	t.Run("accepts flags after positional arguments", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		output := fs.String("o", "", "")
+
+		args, err := parseFlags(fs, []string{"in.dot", "-o", "out.svg", "-"})
+
+		asrt.NoError(err)
+		asrt.Equal([]string{"in.dot", "-"}, args)
+		asrt.Equal("out.svg", *output)
+	})
+}
+
+func TestFormatFromPath(t *testing.T) {
+	t.Run("uses the lowercased extension", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		asrt.Equal("svg", string(formatFromPath("out/graph.SVG")))
+		asrt.Equal("dot", string(formatFromPath("graph.dot")))
+		asrt.Empty(formatFromPath("graph"))
+	})
+}
+
+func TestEnv_WriteOutput(t *testing.T) {
+	t.Run("removes the file when writing fails", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		path := filepath.Join(t.TempDir(), "out.svg")
+		e := &env{stdout: &bytes.Buffer{}}
+
+		err := e.writeOutput(path, func(w io.Writer) error {
+			_, _ = w.Write([]byte("partial"))
+			return os.ErrInvalid
+		})
+
+		asrt.ErrorIs(err, os.ErrInvalid)
+		asrt.NoFileExists(path)
+	})
+}
@@ -0,0 +1,114 @@
+// ABOUTME: Computes and prints the summary statistics shown by goraffe stats.
+// ABOUTME: Counts nodes, edges, subgraphs and clusters, and summarizes node degrees.
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mikowitz/goraffe"
+)
+
+// degreeStats summarizes one kind of node degree across a graph.
+type degreeStats struct {
+	min, max int
+	maxNode  string // First node with the maximum degree
+	mean     float64
+}
+
+// graphStats holds the summary printed by goraffe stats.
+type graphStats struct {
+	name      string
+	directed  bool
+	strict    bool
+	nodes     int
+	edges     int
+	subgraphs int
+	clusters  int
+	in, out   degreeStats // Only used for directed graphs
+	degree    degreeStats // In-degree plus out-degree, so a self-loop counts twice
+}
+
+// computeStats summarizes the graph, counting subgraphs at every nesting level.
+func computeStats(g *goraffe.Graph) graphStats {
+	s := graphStats{
+		name:     g.Name(),
+		directed: g.IsDirected(),
+		strict:   g.IsStrict(),
+		nodes:    len(g.Nodes()),
+		edges:    len(g.Edges()),
+	}
+
+	pending := g.Subgraphs()
+	for len(pending) > 0 {
+		sg := pending[0]
+		pending = append(pending[1:], sg.Subgraphs()...)
+
+		s.subgraphs++
+		if sg.IsCluster() {
+			s.clusters++
+		}
+	}
+
+	s.in = degrees(g, g.InDegree)
+	s.out = degrees(g, g.OutDegree)
+	s.degree = degrees(g, func(id string) int { return g.InDegree(id) + g.OutDegree(id) })
+
+	return s
+}
+
+// degrees summarizes degree(id) over every node in the graph.
+func degrees(g *goraffe.Graph, degree func(id string) int) degreeStats {
+	nodes := g.Nodes()
+	if len(nodes) == 0 {
+		return degreeStats{}
+	}
+
+	d := degreeStats{min: degree(nodes[0].ID())}
+	total := 0
+	for _, n := range nodes {
+		value := degree(n.ID())
+		total += value
+		d.min = min(d.min, value)
+		if value > d.max || d.maxNode == "" {
+			d.max, d.maxNode = value, n.ID()
+		}
+	}
+	d.mean = float64(total) / float64(len(nodes))
+
+	return d
+}
+
+// writeStats writes the summary as aligned "label: value" lines.
+func writeStats(w io.Writer, s graphStats) {
+	kind := "graph"
+	if s.directed {
+		kind = "digraph"
+	}
+	if s.strict {
+		kind = "strict " + kind
+	}
+
+	if s.name != "" {
+		_, _ = fmt.Fprintf(w, "%-12s%s\n", "name:", s.name)
+	}
+	_, _ = fmt.Fprintf(w, "%-12s%s\n", "type:", kind)
+	_, _ = fmt.Fprintf(w, "%-12s%d\n", "nodes:", s.nodes)
+	_, _ = fmt.Fprintf(w, "%-12s%d\n", "edges:", s.edges)
+	_, _ = fmt.Fprintf(w, "%-12s%d\n", "subgraphs:", s.subgraphs)
+	_, _ = fmt.Fprintf(w, "%-12s%d\n", "clusters:", s.clusters)
+
+	if s.nodes == 0 {
+		return
+	}
+	if s.directed {
+		writeDegree(w, "in-degree:", s.in)
+		writeDegree(w, "out-degree:", s.out)
+	}
+	writeDegree(w, "degree:", s.degree)
+}
+
+// writeDegree writes a single degree summary line.
+func writeDegree(w io.Writer, label string, d degreeStats) {
+	_, _ = fmt.Fprintf(w, "%-12smin %d, max %d (%s), mean %.2f\n", label, d.min, d.max, d.maxNode, d.mean)
+}
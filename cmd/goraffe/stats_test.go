@@ -0,0 +1,48 @@
+// ABOUTME: Tests for the statistics computed by goraffe stats.
+// ABOUTME: Verifies subgraph counting and degree summaries for directed and undirected graphs.
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mikowitz/goraffe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeStats(t *testing.T) {
+	t.Run("counts a self-loop twice towards degree", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := goraffe.NewGraph(goraffe.Undirected)
+		a, b := goraffe.NewNode("a"), goraffe.NewNode("b")
+		_, _ = g.AddEdge(a, a)
+		_, _ = g.AddEdge(a, b)
+
+		s := computeStats(g)
+
+		asrt.Equal(degreeStats{min: 1, max: 3, maxNode: "a", mean: 2}, s.degree)
+	})
+
+	t.Run("handles an empty graph", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		var buf bytes.Buffer
+		writeStats(&buf, computeStats(goraffe.NewGraph(goraffe.Strict)))
+
+		asrt.Equal("type:       strict graph\nnodes:      0\nedges:      0\nsubgraphs:  0\nclusters:   0\n", buf.String())
+	})
+
+	t.Run("omits in and out degree for undirected graphs", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := goraffe.NewGraph(goraffe.Undirected)
+		_ = g.AddNode(goraffe.NewNode("a"))
+
+		var buf bytes.Buffer
+		writeStats(&buf, computeStats(g))
+
+		asrt.NotContains(buf.String(), "in-degree")
+		asrt.Contains(buf.String(), "degree:     min 0, max 0 (a), mean 0.00\n")
+	})
+}
@@ -0,0 +1,80 @@
+// ABOUTME: Attaches free-form comments to graphs, subgraphs, nodes and edges.
+// ABOUTME: Comments are written next to their element in DOT output and recovered by the parser.
+package goraffe
+
+import "strings"
+
+// SetComment attaches a free-form comment to the graph, written before the graph
+// declaration in DOT output. Multi-line comments are written one line at a time.
+// An empty string removes the comment.
+//
+// Example:
+//
+//	g.SetComment("Generated by deps-graph; do not edit")
+func (g *Graph) SetComment(text string) {
+	g.comment = text
+}
+
+// Comment returns the comment attached to the graph, or an empty string if there is none.
+func (g *Graph) Comment() string {
+	return g.comment
+}
+
+// SetComment attaches a free-form comment to the subgraph, written before the subgraph
+// declaration in DOT output. An empty string removes the comment.
+func (sg *Subgraph) SetComment(text string) {
+	sg.comment = text
+}
+
+// Comment returns the comment attached to the subgraph, or an empty string if there is none.
+func (sg *Subgraph) Comment() string {
+	return sg.comment
+}
+
+// SetComment attaches a free-form comment to the node, written before the node
+// statement in DOT output. An empty string removes the comment.
+//
+// Example:
+//
+//	cache := goraffe.NewNode("cache")
+//	cache.SetComment("Added to absorb read load on the primary database")
+func (n *Node) SetComment(text string) {
+	n.comment = text
+}
+
+// Comment returns the comment attached to the node, or an empty string if there is none.
+func (n *Node) Comment() string {
+	return n.comment
+}
+
+// SetComment attaches a free-form comment to the edge, written before the edge
+// statement in DOT output. An empty string removes the comment.
+func (e *Edge) SetComment(text string) {
+	e.comment = text
+}
+
+// Comment returns the comment attached to the edge, or an empty string if there is none.
+func (e *Edge) Comment() string {
+	return e.comment
+}
+
+// commentText returns the text of a DOT comment without its delimiters. Each line is
+// trimmed, and the leading " * " that block comments conventionally carry on
+// continuation lines is removed.
+func commentText(raw string) string {
+	if body, ok := strings.CutPrefix(raw, "//"); ok {
+		return strings.TrimSpace(body)
+	}
+
+	body := strings.TrimSuffix(strings.TrimPrefix(raw, "/*"), "*/")
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "*" {
+			line = ""
+		}
+		lines[i] = strings.TrimPrefix(line, "* ")
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
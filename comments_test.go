@@ -0,0 +1,113 @@
+// ABOUTME: Tests for comments attached to graphs, subgraphs, nodes and edges.
+// ABOUTME: Verifies the comment accessors, DOT output placement and comment text extraction.
+package goraffe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComment_Accessors(t *testing.T) {
+	t.Run("elements have no comment by default", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		e, _ := g.AddEdge(NewNode("A"), NewNode("B"))
+		sg := g.Subgraph("sub", func(*Subgraph) {})
+
+		asrt.Empty(g.Comment())
+		asrt.Empty(sg.Comment())
+		asrt.Empty(g.GetNode("A").Comment())
+		asrt.Empty(e.Comment())
+	})
+
+	t.Run("SetComment replaces the comment", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		n := NewNode("A")
+		n.SetComment("first")
+		n.SetComment("second")
+
+		asrt.Equal("second", n.Comment())
+	})
+}
+
+func TestComment_DOTOutput(t *testing.T) {
+	t.Run("writes comments before their elements", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		g.SetComment("Generated file\ndo not edit")
+		a := NewNode("A")
+		a.SetComment("entry point")
+		g.Subgraph("cluster_0", func(s *Subgraph) {
+			s.SetComment("workers")
+			_ = s.AddNode(NewNode("B"))
+		})
+		e, _ := g.AddEdge(a, g.GetNode("B"))
+		e.SetComment("dispatch")
+
+		expected := "// Generated file\n// do not edit\ndigraph {\n" +
+			"\t// workers\n\tsubgraph \"cluster_0\" {\n\t\t\"B\";\n\t}\n" +
+			"\t// entry point\n\t\"A\";\n" +
+			"\t// dispatch\n\t\"A\" -> \"B\";\n}"
+		asrt.Equal(expected, g.String())
+	})
+
+	t.Run("element comments come before printer comments", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		n := NewNode("A")
+		n.SetComment("own")
+		_ = g.AddNode(n)
+
+		output := NewDOTPrinter(WithNodeComments(func(*Node) string { return "extra" })).Print(g)
+
+		asrt.Contains(output, "\t// own\n\t// extra\n\t\"A\";\n")
+	})
+
+	t.Run("block comments", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		a, b := NewNode("A"), NewNode("B")
+		a.SetComment("single line")
+		b.SetComment("two\nlines */")
+		_ = g.AddNode(a)
+		_ = g.AddNode(b)
+
+		output := NewDOTPrinter(WithBlockComments()).Print(g)
+
+		asrt.Contains(output, "\t/* single line */\n\t\"A\";\n")
+		asrt.Contains(output, "\t/*\n\t * two\n\t * lines * /\n\t */\n\t\"B\";\n")
+
+		compact := NewDOTPrinter(WithCompact()).Print(g)
+		asrt.Contains(compact, `/* two lines * / */ "B";`)
+	})
+
+	t.Run("clones keep comments", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		g.SetComment("graph")
+		n := NewNode("A")
+		n.SetComment("node")
+		_ = g.AddNode(n)
+
+		clone := g.Clone()
+
+		asrt.Equal("graph", clone.Comment())
+		asrt.Equal("node", clone.GetNode("A").Comment())
+	})
+}
+
+func TestCommentText(t *testing.T) {
+	asrt := assert.New(t)
+
+	asrt.Equal("note", commentText("//   note  "))
+	asrt.Equal("note", commentText("/* note */"))
+	asrt.Equal("one\n\ntwo", commentText("/*\n * one\n *\n * two\n */"))
+	asrt.Equal("a * b", commentText("/* a * b */"))
+}
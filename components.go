@@ -0,0 +1,98 @@
+// ABOUTME: Splits a graph into its weakly connected components, like Graphviz's ccomps.
+// ABOUTME: Each component becomes a standalone graph that can be rendered independently.
+package goraffe
+
+// ConnectedComponents splits g into one graph per weakly connected component, ignoring
+// edge direction. Components are ordered by their first node in Nodes(), and each keeps
+// the insertion order of its nodes and edges. Every component carries over g's name,
+// type, graph attributes and default node and edge attributes, along with the portion
+// of the subgraph hierarchy that contains its nodes.
+// The components share their Node and Edge values with g, so they can be rendered
+// concurrently as long as neither g nor the components are modified meanwhile.
+//
+// Example:
+//
+//	for i, component := range goraffe.ConnectedComponents(g) {
+//	    component.RenderToFile(goraffe.SVG, fmt.Sprintf("component_%d.svg", i))
+//	}
+func ConnectedComponents(g *Graph) []*Graph {
+	component := make(map[string]int, len(g.nodeOrder))
+	count := 0
+
+	for _, start := range g.nodeOrder {
+		if _, assigned := component[start.ID()]; assigned {
+			continue
+		}
+
+		component[start.ID()] = count
+		pending := []string{start.ID()}
+		for len(pending) > 0 {
+			id := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+
+			for _, n := range g.Neighbors(id) {
+				if _, assigned := component[n.ID()]; !assigned {
+					component[n.ID()] = count
+					pending = append(pending, n.ID())
+				}
+			}
+		}
+		count++
+	}
+
+	// Distribute nodes, edges and subgraph members in one pass rather than projecting
+	// g once per component, which is quadratic for graphs with many small components
+	components := make([]*Graph, count)
+	for i := range components {
+		components[i] = g.derive()
+	}
+
+	for _, n := range g.nodeOrder {
+		_ = components[component[n.ID()]].AddNode(n) // Safe to ignore - nodes in a graph are never nil
+	}
+
+	for e := range g.edges.all() {
+		// Both endpoints of an edge always share a component
+		components[component[e.from.ID()]].addEdge(e)
+	}
+
+	for _, sg := range g.subgraphs {
+		for i, part := range sg.partition(components, component) {
+			components[i].subgraphs = append(components[i].subgraphs, part)
+		}
+	}
+
+	return components
+}
+
+// partition splits this subgraph by the component of each member, returning the part
+// for each component that holds any of its nodes, keyed by component index. Each part
+// belongs to the matching graph in components and keeps the subgraph's attributes and
+// the member order.
+func (sg *Subgraph) partition(components []*Graph, component map[string]int) map[int]*Subgraph {
+	parts := make(map[int]*Subgraph)
+	part := func(i int) *Subgraph {
+		if _, exists := parts[i]; !exists {
+			parts[i] = sg.derive(components[i])
+		}
+		return parts[i]
+	}
+
+	for _, n := range sg.nodeOrder {
+		part(component[n.ID()]).addNode(n)
+	}
+
+	for _, nested := range sg.subgraphs {
+		for i, p := range nested.partition(components, component) {
+			part(i).subgraphs = append(part(i).subgraphs, p)
+		}
+	}
+
+	for e := range sg.edges.all() {
+		if p, exists := parts[component[e.from.ID()]]; exists {
+			p.edges.add(e)
+		}
+	}
+
+	return parts
+}
@@ -0,0 +1,114 @@
+// ABOUTME: Tests for splitting graphs into weakly connected components.
+// ABOUTME: Verifies component membership, ordering and carried-over attributes and subgraphs.
+package goraffe
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectedComponents(t *testing.T) {
+	t.Run("splits on weak connectivity", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := buildDirected([2]string{"A", "B"}, [2]string{"C", "D"}, [2]string{"E", "B"})
+		_ = g.AddNode(NewNode("F"))
+
+		components := ConnectedComponents(g)
+
+		asrt.Len(components, 3)
+		asrt.Equal([]string{"A", "B", "E"}, nodeIDs(components[0].Nodes()), "expected edge direction to be ignored")
+		asrt.Equal([]string{"A->B", "E->B"}, edgePairs(components[0].Edges()))
+		asrt.Equal([]string{"C", "D"}, nodeIDs(components[1].Nodes()))
+		asrt.Equal([]string{"F"}, nodeIDs(components[2].Nodes()))
+		asrt.Empty(components[2].Edges())
+	})
+
+	t.Run("empty graph has no components", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		asrt.Empty(ConnectedComponents(NewGraph()))
+	})
+
+	t.Run("carries over attributes, defaults and subgraphs", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(
+			Undirected,
+			WithGraphLabel("services"),
+			WithDefaultNodeAttrs(WithBoxShape()),
+			WithDefaultEdgeAttrs(WithEdgeColor("gray")),
+		)
+		g.Subgraph("cluster_outer", func(o *Subgraph) {
+			o.SetLabel("outer")
+			o.Subgraph("cluster_a", func(s *Subgraph) {
+				_, _ = s.AddEdge(NewNode("A1"), NewNode("A2"))
+			})
+			o.Subgraph("cluster_b", func(s *Subgraph) {
+				_ = s.AddNode(NewNode("B1"))
+			})
+		})
+
+		components := ConnectedComponents(g)
+
+		asrt.Len(components, 2)
+		for _, c := range components {
+			asrt.False(c.IsDirected())
+			asrt.Equal("services", c.Attrs().Label())
+			asrt.Equal(ShapeBox, c.DefaultNodeAttrs().Shape())
+			asrt.Equal("gray", c.DefaultEdgeAttrs().Color())
+		}
+
+		outer := components[0].Subgraphs()
+		asrt.Len(outer, 1)
+		asrt.Equal("outer", outer[0].Attrs().Label())
+		asrt.Len(outer[0].Subgraphs(), 1, "expected the unrelated nested cluster to be pruned")
+		asrt.Equal("cluster_a", outer[0].Subgraphs()[0].Name())
+		asrt.Len(outer[0].Subgraphs()[0].Edges(), 1)
+
+		asrt.Equal("cluster_b", components[1].Subgraphs()[0].Subgraphs()[0].Name())
+		asrt.NotContains(components[1].String(), "A1")
+	})
+
+	t.Run("splits graphs with many components", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := manyComponentsGraph(5000)
+
+		components := ConnectedComponents(g)
+
+		asrt.Len(components, 5000)
+		for i, c := range components {
+			asrt.Equal([]string{"A" + strconv.Itoa(i), "B" + strconv.Itoa(i)}, nodeIDs(c.Nodes()))
+			asrt.Len(c.Edges(), 1)
+			asrt.Len(c.Subgraphs(), 1)
+			asrt.Equal([]string{"A" + strconv.Itoa(i)}, nodeIDs(c.Subgraphs()[0].Nodes()))
+		}
+	})
+}
+
+func BenchmarkConnectedComponents(b *testing.B) {
+	g := manyComponentsGraph(20000)
+
+	for b.Loop() {
+		ConnectedComponents(g)
+	}
+}
+
+// manyComponentsGraph builds a graph of n components, each an edge Ai->Bi, with every
+// Ai in one shared cluster.
+func manyComponentsGraph(n int) *Graph {
+	g := NewGraph(Directed)
+	g.Subgraph("cluster_sources", func(s *Subgraph) {
+		for i := range n {
+			_ = s.AddNode(NewNode("A" + strconv.Itoa(i)))
+		}
+	})
+	for i := range n {
+		_, _ = g.AddEdge(g.GetNode("A"+strconv.Itoa(i)), NewNode("B"+strconv.Itoa(i)))
+	}
+
+	return g
+}
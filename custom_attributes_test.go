@@ -461,3 +461,43 @@ func TestCustomAttributes_MultipleCalls_Accumulate(t *testing.T) {
 		asrt.Equal("red", n.Attrs().Color(), "expected typed Color to be set")
 	})
 }
+
+func TestCustomAttributes_List_Sorted(t *testing.T) {
+	t.Run("node attributes list custom keys in sorted order", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		n := NewNode("A", WithNodeAttribute("tooltip", "t"), WithNodeAttribute("URL", "u"),
+			WithNodeAttribute("peripheries", "2"))
+
+		asrt.Equal([]string{`URL="u"`, `peripheries="2"`, `tooltip="t"`}, n.Attrs().List())
+	})
+
+	t.Run("edge attributes list custom keys in sorted order", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		e, _ := NewGraph().AddEdge(NewNode("A"), NewNode("B"), WithEdgeColor("red"),
+			WithEdgeAttribute("penwidth", "2"), WithEdgeAttribute("dir", "both"))
+
+		asrt.Equal([]string{`color="red"`, `dir="both"`, `penwidth="2"`}, e.Attrs().List())
+	})
+
+	t.Run("graph attributes list custom keys in sorted order", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(WithGraphAttribute("ratio", "fill"), WithGraphAttribute("margin", "0"))
+
+		asrt.Equal([]string{"\tmargin=\"0\";", "\tratio=\"fill\";"}, g.Attrs().List())
+	})
+
+	t.Run("subgraph attributes list custom keys after typed ones in sorted order", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		sg := NewGraph().Subgraph("cluster_0", func(s *Subgraph) {
+			s.SetAttribute("penwidth", "2")
+			s.SetLabel("group")
+			s.SetAttribute("labeljust", "l")
+		})
+
+		asrt.Equal([]string{`label="group"`, `labeljust="l"`, `penwidth="2"`}, sg.Attrs().List())
+	})
+}
@@ -60,6 +60,13 @@
 //	    goraffe.WithArrowHead(goraffe.ArrowDot),
 //	)
 //
+// Pin edge ends to a side of a node with compass points:
+//
+//	e := g.AddEdge(n1, n2,
+//	    goraffe.FromCompass(n1, goraffe.CompassE),
+//	    goraffe.ToCompass(n2, goraffe.CompassW),
+//	)
+//
 // # Graph Attributes
 //
 // Customize the overall graph appearance:
@@ -80,6 +87,23 @@
 //	f, _ := os.Create("graph.dot")
 //	g.WriteDOT(f)
 //
+// Output is deterministic: the same graph always produces byte-identical DOT, so
+// generated files can be checked in and diffed. See Graph.String for the ordering rules.
+//
+// Comments attached with SetComment are written next to their graph, subgraph, node or
+// edge, and ParseString attaches the comments before each statement to what it declares.
+// Attribute and default statements declare nothing, so their comments are added to the
+// enclosing graph or subgraph:
+//
+//	cache.SetComment("Absorbs read load on the primary")
+//
+// Use a DOTPrinter to match a house style for generated files:
+//
+//	p := goraffe.NewDOTPrinter(goraffe.WithIndent("  "), goraffe.WithMinimalQuoting())
+//	fmt.Println(p.Print(g))
+//	// WriteDOT accepts the same options
+//	g.WriteDOT(f, goraffe.WithAttributePerLine())
+//
 // # Rendering
 //
 // Render graphs to various output formats using Graphviz:
@@ -94,7 +118,24 @@
 //	var buf bytes.Buffer
 //	g.Render(goraffe.PNG, &buf, goraffe.WithLayout(goraffe.LayoutNeato))
 //
-// Supported formats: PNG, SVG, PDF, DOT
+//	// Bound how long Graphviz may run, by context or by option
+//	g.RenderContext(r.Context(), goraffe.SVG, w)
+//	g.Render(goraffe.SVG, w, goraffe.WithTimeout(5*time.Second))
+//
+// Formats range from images (PNG, SVG, PDF, JPEG, ...) to layout data (JSON, XDOT, Plain),
+// and variants such as PNG.Variant("cairo", "") pick a specific renderer. Which formats
+// work depends on the installed Graphviz; SupportedFormats asks it, and Render fails
+// fast with ErrUnsupportedFormat for anything it does not support.
+//
+// To draw a graph yourself, let Graphviz compute the layout and read back the geometry,
+// keyed by your own nodes, edges and clusters:
+//
+//	result, err := g.Layout(ctx)
+//	center := result.Nodes[start].Center
+//
+// When only coordinates and names are needed, RenderPlain and ParsePlain work with
+// Graphviz's lighter plain and plain-ext text formats instead.
+//
 // Supported layouts: dot, neato, fdp, sfdp, twopi, circo, osage, patchwork
 //
 // # Parsing
@@ -111,6 +152,41 @@
 //	// Parse from file path
 //	g, _ := goraffe.ParseFile("graph.dot")
 //
+// To tidy hand-written DOT without rebuilding it, FormatDOT reformats the source into
+// a canonical layout while keeping comments, # lines and statement order intact:
+//
+//	formatted, err := goraffe.FormatDOT(src)
+//
+// # Algorithms
+//
+// Validate graph structure before rendering:
+//
+//	// Order a build pipeline, failing on cycles
+//	order, err := g.TopologicalSort()
+//
+//	// List the cycles that make a graph cyclic
+//	cycles := g.FindCycles()
+//
+//	// Group mutually reachable nodes, highlighting them as clusters
+//	sccs := g.StronglyConnectedComponents(goraffe.WithSCCClusters())
+//
+//	// Find the cheapest route using edge weights, then emphasize it
+//	p, err := goraffe.ShortestPath(g, "A", "D")
+//	p.Highlight("red", 2.0)
+//
+//	// Drop edges implied by longer paths, like Graphviz's tred
+//	reduced, err := goraffe.TransitiveReduction(g)
+//
+//	// Split a disconnected graph so each part can be rendered on its own, like ccomps
+//	components := goraffe.ConnectedComponents(g)
+//
+//	// Extract everything within 2 hops of a node as its own graph
+//	around := g.Neighborhood("X", 2, goraffe.DirectionBoth)
+//
+//	// Copy a graph, or combine another graph into it
+//	copied := g.Clone()
+//	err := g.Merge(other, goraffe.MergeAttributes)
+//
 // # Requirements
 //
 // Graphviz must be installed on your system for rendering functionality to work.
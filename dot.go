@@ -1,6 +1,11 @@
 package goraffe
 
-import "strings"
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
 
 // escapeDOTString escapes special characters in a string for DOT output.
 // It escapes:
@@ -19,9 +24,95 @@ func escapeDOTString(s string) string {
 	return s
 }
 
+// labelEscapes are the characters that form a Graphviz label escape when they follow a
+// backslash: line breaks (\n, \l, \r) and object names (\N, \G, \E, \T, \H, \L).
+const labelEscapes = "nlrNGETHL"
+
+// labelAttributes are the attributes whose values Graphviz reads as labels, so their
+// label escapes must reach Graphviz unchanged.
+var labelAttributes = map[string]bool{
+	"label":     true,
+	"xlabel":    true,
+	"headlabel": true,
+	"taillabel": true,
+}
+
+// isLabelEscape reports whether the byte at s[i] is a backslash starting a label escape.
+func isLabelEscape(s string, i int) bool {
+	return s[i] == '\\' && i+1 < len(s) && strings.IndexByte(labelEscapes, s[i+1]) >= 0
+}
+
+// escapeLabelString escapes a label for DOT output like escapeDOTString, except that
+// label escapes such as \l are written as-is for Graphviz to interpret.
+func escapeLabelString(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case isLabelEscape(s, i):
+			sb.WriteString(s[i : i+2])
+			i++
+		case ch == '\\' || ch == '"':
+			sb.WriteByte('\\')
+			sb.WriteByte(ch)
+		case ch == '\n':
+			sb.WriteString(`\n`)
+		default:
+			sb.WriteByte(ch)
+		}
+	}
+
+	return sb.String()
+}
+
 // quoteDOTID quotes and escapes a DOT identifier (node ID or graph name).
 // Per DOT specification and for safety, we always quote identifiers.
 // The string is escaped using escapeDOTString before being quoted.
 func quoteDOTID(s string) string {
 	return `"` + escapeDOTString(s) + `"`
 }
+
+// attribute is a single name=value pair in a DOT attribute list.
+// HTML values are written verbatim, including their enclosing angle brackets.
+type attribute struct {
+	name  string
+	value string
+	html  bool
+}
+
+// String returns the attribute in name="value" form, or name=<...> for HTML values.
+func (a attribute) String() string {
+	if a.html {
+		return a.name + "=" + a.value
+	}
+
+	if labelAttributes[a.name] {
+		return a.name + `="` + escapeLabelString(a.value) + `"`
+	}
+
+	return a.name + "=" + quoteDOTID(a.value)
+}
+
+// formatAttributes returns the name="value" form of each attribute.
+func formatAttributes(attrs []attribute) []string {
+	formatted := make([]string, 0, len(attrs))
+	for _, attr := range attrs {
+		formatted = append(formatted, attr.String())
+	}
+
+	return formatted
+}
+
+// appendCustom appends custom attributes to attrs, sorted by name so that attributes
+// held in maps are rendered in the same order on every run.
+func appendCustom(attrs []attribute, custom map[string]string) []attribute {
+	for _, name := range slices.Sorted(maps.Keys(custom)) {
+		attrs = append(attrs, attribute{name: name, value: custom[name]})
+	}
+
+	return attrs
+}
+
+// formatFloat formats a numeric attribute value in its shortest representation.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -3693,3 +3693,124 @@ func TestDOT_ComplexGraph_WithRanks(t *testing.T) {
 	asrt.Contains(output, `"process3" -> "end";`)
 	asrt.Contains(output, `"process4" -> "end";`)
 }
+
+func TestDOT_Subgraph_DefaultAttrs(t *testing.T) {
+	t.Run("renders defaults inside the subgraph block", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		sg := g.Subgraph("cluster_0", func(s *Subgraph) {
+			s.SetLabel("styled")
+			s.SetDefaultNodeAttrs(WithBoxShape(), WithColor("red"))
+			s.SetDefaultEdgeAttrs(WithEdgeColor("blue"))
+			s.Subgraph("cluster_1", func(inner *Subgraph) {
+				inner.SetDefaultNodeAttrs(WithCircleShape())
+				_ = inner.AddNode(NewNode("B"))
+			})
+		})
+
+		output := g.String()
+		asrt.Contains(output, "\tsubgraph \"cluster_0\" {\n\t\tlabel=\"styled\";\n\t\tnode [color=\"red\", shape=\"box\"];\n\t\tedge [color=\"blue\"];\n")
+		asrt.Contains(output, "\t\tsubgraph \"cluster_1\" {\n\t\t\tnode [shape=\"circle\"];\n\t\t\t\"B\";\n")
+		asrt.NotContains(output, "\tnode [shape=\"box\"", "expected subgraph defaults not to leak to the graph")
+
+		asrt.Contains(sg.String(), "\tnode [color=\"red\", shape=\"box\"];\n\tedge [color=\"blue\"];\n")
+	})
+}
+
+func TestDOT_Subgraph_EdgesInScope(t *testing.T) {
+	t.Run("renders subgraph edges inside the subgraph block", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		a, b, c := NewNode("A"), NewNode("B"), NewNode("C")
+		g.Subgraph("cluster_0", func(s *Subgraph) {
+			_ = s.AddNode(a)
+			_ = s.AddNode(b)
+			_, _ = s.AddEdge(a, b)
+		})
+		_, _ = g.AddEdge(b, c)
+
+		output := g.String()
+		asrt.Contains(output, "		\"A\" -> \"B\";\n\t}\n")
+		asrt.Contains(output, "\n\t\"B\" -> \"C\";\n")
+		asrt.Equal(1, strings.Count(output, `"A" -> "B"`), "expected subgraph edge to be rendered once")
+	})
+
+	t.Run("renders edges in the innermost subgraph that holds them", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Undirected)
+		a, b := NewNode("A"), NewNode("B")
+		outer := g.Subgraph("outer", func(s *Subgraph) {
+			s.Subgraph("inner", func(inner *Subgraph) {
+				_ = inner.AddNode(a)
+				_ = inner.AddNode(b)
+				_, _ = inner.AddEdge(a, b)
+			})
+		})
+
+		output := g.String()
+		asrt.Contains(output, "\t\t\t\"A\" -- \"B\";\n\t\t}\n")
+		asrt.Equal(1, strings.Count(output, `"A" -- "B"`))
+
+		asrt.Contains(outer.String(), "\t\t\"A\" -- \"B\";\n\t}\n}")
+	})
+
+	t.Run("Subgraph.String matches its block in Graph.String", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		a := NewNode("A")
+		sg := g.Subgraph("cluster_0", func(s *Subgraph) {
+			s.SetLabel("group")
+			s.SetDefaultEdgeAttrs(WithEdgeColor("red"))
+			_ = s.AddNode(a)
+			_, _ = s.AddEdge(a, a)
+		})
+
+		var indented []string
+		for _, line := range strings.Split(sg.String(), "\n") {
+			indented = append(indented, "\t"+line)
+		}
+		asrt.Contains(g.String(), strings.Join(indented, "\n")+"\n")
+	})
+}
+
+func TestDOT_Deterministic(t *testing.T) {
+	t.Run("repeated calls produce identical output", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		build := func() *Graph {
+			g := NewGraph(Directed, WithGraphAttribute("ratio", "fill"), WithGraphAttribute("margin", "0"))
+			g.Subgraph("cluster_0", func(s *Subgraph) {
+				s.SetAttribute("penwidth", "2")
+				s.SetAttribute("labeljust", "l")
+				for _, id := range []string{"E", "D", "C", "B", "A"} {
+					_ = s.AddNode(NewNode(id, WithNodeAttribute("tooltip", id), WithNodeAttribute("URL", id)))
+				}
+			})
+			_, _ = g.AddEdge(g.GetNode("A"), g.GetNode("E"),
+				WithEdgeAttribute("penwidth", "2"), WithEdgeAttribute("dir", "both"))
+			return g
+		}
+
+		expected := build().String()
+		for range 20 {
+			asrt.Equal(expected, build().String())
+		}
+	})
+
+	t.Run("subgraph nodes render in insertion order", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		g.Subgraph("cluster_0", func(s *Subgraph) {
+			for _, id := range []string{"C", "A", "B"} {
+				_ = s.AddNode(NewNode(id))
+			}
+		})
+
+		asrt.Contains(g.String(), "\t\t\"C\";\n\t\t\"A\";\n\t\t\"B\";\n")
+	})
+}
@@ -1,16 +1,12 @@
 package goraffe
 
-import (
-	"sort"
-	"strings"
-)
-
 // Edge represents a connection between two nodes in a graph.
 // Edges can be directed (arrows) or undirected (lines) depending on the graph type.
 // Use Graph.AddEdge to create edges.
 type Edge struct {
 	from, to *Node
 	attrs    *EdgeAttributes
+	comment  string
 }
 
 // From returns the source node of the edge.
@@ -32,40 +28,5 @@ func (e *Edge) Attrs() *EdgeAttributes {
 }
 
 func (e *Edge) ToString(directed bool) string {
-	builder := strings.Builder{}
-
-	// Write source node (and optional port)
-	builder.WriteString(quoteDOTID(e.from.ID()))
-	if e.attrs.fromPort != nil {
-		builder.WriteString(":")
-		builder.WriteString(quoteDOTID(e.attrs.fromPort.ID()))
-	}
-
-	// Write edge operator
-	if directed {
-		builder.WriteString(" -> ")
-	} else {
-		builder.WriteString(" -- ")
-	}
-
-	// Write destination node (and optional port)
-	builder.WriteString(quoteDOTID(e.to.ID()))
-	if e.attrs.toPort != nil {
-		builder.WriteString(":")
-		builder.WriteString(quoteDOTID(e.attrs.toPort.ID()))
-	}
-
-	// Write attributes
-	attrs := e.attrs.List()
-	var attrsStr string
-
-	if len(attrs) > 0 {
-		sort.Strings(attrs)
-		attrsStr = "[" + strings.Join(attrs, ", ") + "]"
-
-		builder.WriteString(" ")
-		builder.WriteString(attrsStr)
-	}
-
-	return builder.String()
+	return defaultPrinter.edge(e, directed, 0)
 }
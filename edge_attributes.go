@@ -1,7 +1,6 @@
 package goraffe
 
 import (
-	"fmt"
 	"maps"
 )
 
@@ -65,6 +64,24 @@ func (a *EdgeAttributes) setCustom(key, value string) {
 	a.custom[key] = value
 }
 
+// clone returns a copy of the attributes with its own custom attribute map.
+func (a *EdgeAttributes) clone() *EdgeAttributes {
+	copied := *a
+	copied.custom = maps.Clone(a.custom)
+
+	return &copied
+}
+
+// merge copies every attribute set in src, including custom attributes, onto a.
+// Values set in src take precedence.
+func (a *EdgeAttributes) merge(src *EdgeAttributes) {
+	src.applyEdge(a)
+
+	for k, v := range src.custom {
+		a.setCustom(k, v)
+	}
+}
+
 // Label returns the edge label. Returns empty string if unset.
 // Note: An empty string return value may indicate either an unset label or a label
 // explicitly set to empty string.
@@ -180,31 +197,36 @@ func (a EdgeAttributes) applyEdge(dst *EdgeAttributes) {
 	}
 }
 
+// List returns a slice of DOT attribute strings for rendering.
+// Only attributes that have been explicitly set are included.
 func (a EdgeAttributes) List() []string {
-	attrs := make([]string, 0)
+	return formatAttributes(a.attributes())
+}
+
+// attributes returns the attributes that have been explicitly set, typed attributes
+// first in declaration order followed by custom attributes sorted by name.
+// Ports are not included, since they are written as part of the edge's endpoints.
+func (a EdgeAttributes) attributes() []attribute {
+	attrs := make([]attribute, 0)
 
 	if a.label != nil {
-		attrs = append(attrs, fmt.Sprintf(`label="%s"`, escapeDOTString(a.Label())))
+		attrs = append(attrs, attribute{name: "label", value: a.Label()})
 	}
 	if a.color != nil {
-		attrs = append(attrs, fmt.Sprintf(`color="%s"`, escapeDOTString(a.Color())))
+		attrs = append(attrs, attribute{name: "color", value: a.Color()})
 	}
 	if a.style != nil {
-		attrs = append(attrs, fmt.Sprintf(`style="%s"`, escapeDOTString(string(a.Style()))))
+		attrs = append(attrs, attribute{name: "style", value: string(a.Style())})
 	}
 	if a.arrowHead != nil {
-		attrs = append(attrs, fmt.Sprintf(`arrowhead="%s"`, escapeDOTString(string(a.ArrowHead()))))
+		attrs = append(attrs, attribute{name: "arrowhead", value: string(a.ArrowHead())})
 	}
 	if a.arrowTail != nil {
-		attrs = append(attrs, fmt.Sprintf(`arrowtail="%s"`, escapeDOTString(string(a.ArrowTail()))))
+		attrs = append(attrs, attribute{name: "arrowtail", value: string(a.ArrowTail())})
 	}
 	if a.weight != nil {
-		attrs = append(attrs, fmt.Sprintf(`weight="%g"`, a.Weight()))
-	}
-
-	for k, v := range a.custom {
-		attrs = append(attrs, fmt.Sprintf(`%s="%s"`, k, escapeDOTString(v)))
+		attrs = append(attrs, attribute{name: "weight", value: formatFloat(a.Weight())})
 	}
 
-	return attrs
+	return appendCustom(attrs, a.custom)
 }
@@ -0,0 +1,87 @@
+// ABOUTME: Holds the edges of a graph or subgraph in insertion order.
+// ABOUTME: Edges can be found and removed in constant time, so removal costs follow the adjacency index.
+package goraffe
+
+import (
+	"iter"
+	"slices"
+)
+
+// edgeList holds edges in insertion order. Removing an edge leaves a nil hole in its
+// place, and holes are compacted away once they outnumber the remaining edges, so
+// removal takes amortized constant time. The zero value is an empty list.
+type edgeList struct {
+	edges []*Edge       // Edges in insertion order, with nil holes for removed edges
+	pos   map[*Edge]int // Position of each edge in edges
+}
+
+// add appends e to the list.
+func (l *edgeList) add(e *Edge) {
+	if l.pos == nil {
+		l.pos = make(map[*Edge]int)
+	}
+	l.pos[e] = len(l.edges)
+	l.edges = append(l.edges, e)
+}
+
+// remove removes e from the list, keeping the order of the remaining edges.
+// Returns false if e is not in the list.
+func (l *edgeList) remove(e *Edge) bool {
+	idx, exists := l.pos[e]
+	if !exists {
+		return false
+	}
+
+	l.edges[idx] = nil
+	delete(l.pos, e)
+
+	if holes := len(l.edges) - len(l.pos); holes > len(l.pos) {
+		l.compact()
+	}
+
+	return true
+}
+
+// compact drops the holes left by removed edges.
+func (l *edgeList) compact() {
+	l.edges = slices.DeleteFunc(l.edges, func(e *Edge) bool { return e == nil })
+	for i, e := range l.edges {
+		l.pos[e] = i
+	}
+}
+
+// contains reports whether e is in the list.
+func (l *edgeList) contains(e *Edge) bool {
+	_, exists := l.pos[e]
+	return exists
+}
+
+// len returns the number of edges in the list.
+func (l *edgeList) len() int {
+	return len(l.pos)
+}
+
+// all iterates over the edges in insertion order.
+func (l *edgeList) all() iter.Seq[*Edge] {
+	return func(yield func(*Edge) bool) {
+		for _, e := range l.edges {
+			if e != nil && !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// slice returns the edges in insertion order. The list is not modified, so the result
+// is a new slice whenever removed edges have left holes.
+func (l *edgeList) slice() []*Edge {
+	if len(l.edges) == len(l.pos) && l.edges != nil {
+		return l.edges
+	}
+
+	edges := make([]*Edge, 0, len(l.pos))
+	for e := range l.all() {
+		edges = append(edges, e)
+	}
+	return edges
+}
@@ -115,6 +115,26 @@ func FromPort(p *Port) EdgeOption {
 	})
 }
 
+// FromCompass specifies which side of the source node this edge connects from.
+// Unlike FromPort, this does not require the node to have an HTML or record label.
+//
+// Example:
+//
+//	e := g.AddEdge(n1, n2, FromCompass(n1, CompassSE))
+func FromCompass(node *Node, c CompassPoint) EdgeOption {
+	return FromPort(&Port{nodeID: node.id, compass: c})
+}
+
+// ToCompass specifies which side of the destination node this edge connects to.
+// Unlike ToPort, this does not require the node to have an HTML or record label.
+//
+// Example:
+//
+//	e := g.AddEdge(n1, n2, ToCompass(n2, CompassNW))
+func ToCompass(node *Node, c CompassPoint) EdgeOption {
+	return ToPort(&Port{nodeID: node.id, compass: c})
+}
+
 // ToPort specifies which port on the destination node this edge connects to.
 // The port must be defined in the destination node's HTML label.
 //
@@ -627,3 +627,45 @@ func TestDOT_Edge_WithBothPorts(t *testing.T) {
 		asrt.Contains(output, "\"A\":\"out\" -> \"B\":\"in\" [label=\"data\"];", "expected edge with both ports and label")
 	})
 }
+
+func TestFromCompass_ToCompass(t *testing.T) {
+	t.Run("sets compass-only ports wired to the nodes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		n1 := NewNode("A", WithBoxShape())
+		n2 := NewNode("B", WithBoxShape())
+
+		e, err := g.AddEdge(n1, n2, FromCompass(n1, CompassE), ToCompass(n2, CompassW))
+		asrt.NoError(err)
+
+		asrt.Equal(CompassE, e.Attrs().FromPort().Compass())
+		asrt.Equal("A", e.Attrs().FromPort().NodeID())
+		asrt.Empty(e.Attrs().FromPort().ID())
+		asrt.Equal(CompassW, e.Attrs().ToPort().Compass())
+		asrt.Equal("B", e.Attrs().ToPort().NodeID())
+	})
+}
+
+func TestDOT_Edge_WithCompass(t *testing.T) {
+	t.Run("outputs compass points unquoted", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		n1 := NewNode("A")
+		n2 := NewNode("B")
+		n3 := NewNode("C")
+
+		port := Cell(Text("output")).Port("p1").GetPort()
+		port.setNodeContext(n1)
+
+		_, err := g.AddEdge(n1, n2, FromPort(port.WithCompass(CompassSE)), ToCompass(n2, CompassN))
+		asrt.NoError(err)
+		_, err = g.AddEdge(n2, n3, FromCompass(n2, CompassAuto))
+		asrt.NoError(err)
+
+		output := g.String()
+		asrt.Contains(output, "\"A\":\"p1\":se -> \"B\":n;", "expected edge with port and compass points")
+		asrt.Contains(output, "\"B\":_ -> \"C\";", "expected edge with compass-only port")
+	})
+}
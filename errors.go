@@ -13,21 +13,27 @@ type RenderError struct {
 	Err error
 	// Stderr contains the stderr output from Graphviz.
 	Stderr string
-	// ExitCode is the exit code from the Graphviz process.
+	// ExitCode is the exit code from the Graphviz process, or -1 if the process was
+	// killed or never started.
 	ExitCode int
 }
 
 // Error implements the error interface.
 func (e *RenderError) Error() string {
+	msg := e.Err.Error()
+	if e.ExitCode >= 0 {
+		msg = fmt.Sprintf("%s (exit code %d)", msg, e.ExitCode)
+	}
+
 	if e.Stderr != "" {
 		// Include a snippet of stderr (first 200 chars) for context
 		stderr := e.Stderr
 		if len(stderr) > 200 {
 			stderr = stderr[:200] + "..."
 		}
-		return fmt.Sprintf("%v (exit code %d): %s", e.Err, e.ExitCode, stderr)
+		return fmt.Sprintf("%s: %s", msg, stderr)
 	}
-	return fmt.Sprintf("%v (exit code %d)", e.Err, e.ExitCode)
+	return msg
 }
 
 // Unwrap returns the underlying error.
@@ -43,4 +49,7 @@ var (
 	ErrInvalidDOT = errors.New("goraffe: invalid DOT syntax")
 	// ErrRenderFailed indicates that rendering failed for an unknown reason.
 	ErrRenderFailed = errors.New("goraffe: rendering failed")
+	// ErrUnsupportedFormat indicates that an output format is malformed or not supported
+	// by the installed Graphviz.
+	ErrUnsupportedFormat = errors.New("goraffe: unsupported output format")
 )
@@ -0,0 +1,93 @@
+// ABOUTME: Extracts standalone graphs around a selection of nodes.
+// ABOUTME: Supports induced subgraphs and breadth-first neighborhoods of a single node.
+package goraffe
+
+// Direction selects which edges are followed when walking away from a node.
+type Direction int
+
+const (
+	// DirectionOut follows edges from tail to head, visiting successors.
+	DirectionOut Direction = iota
+	// DirectionIn follows edges from head to tail, visiting predecessors.
+	DirectionIn
+	// DirectionBoth follows edges regardless of direction, visiting all neighbors.
+	DirectionBoth
+)
+
+// InducedSubgraph returns a new graph containing the nodes with the given IDs and every
+// edge of g whose endpoints are both among them. IDs not present in g are ignored.
+// The result keeps g's name, type, graph attributes, default node and edge attributes,
+// and the portion of the subgraph hierarchy that contains the selected nodes.
+// Nodes, edges and subgraphs are deep copies, so the result can be modified or rendered
+// without affecting g.
+//
+// Example:
+//
+//	core := g.InducedSubgraph("api", "db", "cache")
+//	core.RenderToFile(goraffe.SVG, "core.svg")
+func (g *Graph) InducedSubgraph(ids ...string) *Graph {
+	selected := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		selected[id] = true
+	}
+
+	return g.extract(selected)
+}
+
+// Neighborhood returns a new graph containing the node with the given ID and every node
+// reachable from it in at most depth steps, following edges in the given direction.
+// Edge direction is ignored in undirected graphs. The result is the induced subgraph of
+// those nodes, as returned by InducedSubgraph. A depth of zero or less selects only the
+// node itself, and an unknown ID yields a graph with no nodes.
+//
+// Example:
+//
+//	// Everything within 2 hops of service X, upstream or downstream
+//	around := g.Neighborhood("X", 2, goraffe.DirectionBoth)
+func (g *Graph) Neighborhood(id string, depth int, direction Direction) *Graph {
+	selected := make(map[string]bool)
+	if g.GetNode(id) == nil {
+		return g.extract(selected)
+	}
+
+	selected[id] = true
+	frontier := []string{id}
+	for step := 0; step < depth && len(frontier) > 0; step++ {
+		var next []string
+		for _, current := range frontier {
+			for _, n := range g.step(current, direction) {
+				if !selected[n.ID()] {
+					selected[n.ID()] = true
+					next = append(next, n.ID())
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return g.extract(selected)
+}
+
+// step returns the nodes one edge away from id in the given direction.
+func (g *Graph) step(id string, direction Direction) []*Node {
+	if !g.directed {
+		return g.Neighbors(id)
+	}
+
+	switch direction {
+	case DirectionOut:
+		return g.Successors(id)
+	case DirectionIn:
+		return g.Predecessors(id)
+	default:
+		return g.Neighbors(id)
+	}
+}
+
+// extract returns a standalone copy of the subgraph of g induced by the selected node IDs.
+func (g *Graph) extract(selected map[string]bool) *Graph {
+	return g.project(
+		func(n *Node) bool { return selected[n.ID()] },
+		func(*Edge) bool { return true },
+	).Clone()
+}
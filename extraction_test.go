@@ -0,0 +1,122 @@
+// ABOUTME: Tests for extracting induced subgraphs and neighborhoods.
+// ABOUTME: Verifies node selection, kept edges, carried-over structure and independence.
+package goraffe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraph_InducedSubgraph(t *testing.T) {
+	t.Run("keeps edges among selected nodes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := buildDirected([2]string{"A", "B"}, [2]string{"B", "C"}, [2]string{"C", "A"}, [2]string{"A", "B"})
+
+		induced := g.InducedSubgraph("B", "A", "missing")
+
+		asrt.Equal([]string{"A", "B"}, nodeIDs(induced.Nodes()), "expected source node order")
+		asrt.Equal([]string{"A->B", "A->B"}, edgePairs(induced.Edges()))
+	})
+
+	t.Run("carries over attributes, defaults and pruned subgraphs", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed, WithName("G"), WithGraphLabel("services"),
+			WithDefaultNodeAttrs(WithBoxShape()), WithDefaultEdgeAttrs(WithEdgeColor("gray")))
+		g.Subgraph("cluster_kept", func(s *Subgraph) {
+			s.SetLabel("kept")
+			_, _ = s.AddEdge(NewNode("A"), NewNode("B"))
+		})
+		g.Subgraph("cluster_dropped", func(s *Subgraph) {
+			_ = s.AddNode(NewNode("C"))
+		})
+
+		induced := g.InducedSubgraph("A", "B")
+
+		asrt.Equal("G", induced.Name())
+		asrt.True(induced.IsDirected())
+		asrt.Equal("services", induced.Attrs().Label())
+		asrt.Equal(ShapeBox, induced.DefaultNodeAttrs().Shape())
+		asrt.Equal("gray", induced.DefaultEdgeAttrs().Color())
+		asrt.Len(induced.Subgraphs(), 1)
+		asrt.Equal("cluster_kept", induced.Subgraphs()[0].Name())
+		asrt.Len(induced.Subgraphs()[0].Edges(), 1)
+	})
+
+	t.Run("result is independent of the source", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := buildDirected([2]string{"A", "B"})
+		induced := g.InducedSubgraph("A", "B")
+
+		asrt.NotSame(g.GetNode("A"), induced.GetNode("A"))
+		asrt.NotSame(g.Edges()[0], induced.Edges()[0])
+
+		induced.RemoveNode("B")
+		asrt.Equal([]string{"A", "B"}, nodeIDs(g.Nodes()))
+		asrt.Len(g.Edges(), 1)
+	})
+}
+
+func TestGraph_Neighborhood(t *testing.T) {
+	// A -> B -> C -> D, E -> B
+	g := buildDirected([2]string{"A", "B"}, [2]string{"B", "C"}, [2]string{"C", "D"}, [2]string{"E", "B"})
+
+	t.Run("follows outgoing edges", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		n := g.Neighborhood("B", 1, DirectionOut)
+
+		asrt.Equal([]string{"B", "C"}, nodeIDs(n.Nodes()))
+		asrt.Equal([]string{"B->C"}, edgePairs(n.Edges()))
+	})
+
+	t.Run("follows incoming edges", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		n := g.Neighborhood("C", 2, DirectionIn)
+
+		asrt.Equal([]string{"A", "B", "C", "E"}, nodeIDs(n.Nodes()))
+		asrt.Equal([]string{"A->B", "B->C", "E->B"}, edgePairs(n.Edges()))
+	})
+
+	t.Run("follows both directions up to depth", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		n := g.Neighborhood("A", 2, DirectionBoth)
+
+		asrt.Equal([]string{"A", "B", "C", "E"}, nodeIDs(n.Nodes()))
+	})
+
+	t.Run("undirected graphs ignore direction", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		u := NewGraph(Undirected)
+		_, _ = u.AddEdge(NewNode("A"), NewNode("B"))
+		_, _ = u.AddEdge(NewNode("C"), NewNode("B"))
+
+		n := u.Neighborhood("B", 1, DirectionOut)
+
+		asrt.Equal([]string{"A", "B", "C"}, nodeIDs(n.Nodes()))
+	})
+
+	t.Run("zero depth selects only the node", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		n := g.Neighborhood("B", 0, DirectionBoth)
+
+		asrt.Equal([]string{"B"}, nodeIDs(n.Nodes()))
+		asrt.Empty(n.Edges())
+	})
+
+	t.Run("unknown node yields empty graph", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		n := g.Neighborhood("missing", 3, DirectionBoth)
+
+		asrt.Empty(n.Nodes())
+		asrt.True(n.IsDirected())
+	})
+}
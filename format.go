@@ -0,0 +1,455 @@
+// ABOUTME: Implements FormatDOT, which reformats DOT source into a canonical layout.
+// ABOUTME: Works on the token stream so comments, statement order and token text are preserved.
+package goraffe
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// formatComment is a comment or # preprocessor line met while formatting.
+type formatComment struct {
+	text string // Raw text, including delimiters
+	line int    // Line on which the comment starts
+}
+
+// formatter reformats DOT source statement by statement. It tracks just enough of the
+// grammar to find statement boundaries, and copies every ID exactly as written.
+type formatter struct {
+	*DOTPrinter
+	lexer    *Lexer
+	tok      Token           // Current token, never a comment
+	raw      string          // Source text of tok
+	comments []formatComment // Comments between the previous token and tok
+	pending  []formatComment // Comments met inside the current statement
+	prevLine int             // Line on which the previous token ended
+	depth    int
+	out      strings.Builder
+}
+
+// FormatDOT reformats DOT source into a canonical layout, like gofmt does for Go.
+// Each statement is written on its own line, indented by nesting depth and terminated
+// by a semicolon; keywords are lowercased and spacing around operators and attribute
+// lists is normalized. Everything else is kept as written: IDs and their quoting,
+// statement order, attribute order, comments, # preprocessor lines and every graph of
+// a multi-graph file. Comments inside a statement are moved onto their own lines
+// before it, and comments after a statement stay on its line.
+//
+// Of the printer options, WithIndent and WithAttributePerLine apply; the others
+// concern how graph values are written and have no effect on formatting.
+// Returns a ParseError if src is not valid DOT.
+//
+// Example:
+//
+//	formatted, err := goraffe.FormatDOT(src, goraffe.WithIndent("  "))
+func FormatDOT(src []byte, opts ...PrinterOption) ([]byte, error) {
+	lexer := NewLexer(string(src))
+	lexer.emitting = true
+	f := &formatter{DOTPrinter: NewDOTPrinter(opts...), lexer: lexer}
+
+	if err := f.file(); err != nil {
+		return nil, (&Parser{lexer: lexer, current: f.tok}).wrapParseError(err)
+	}
+
+	return []byte(f.out.String()), nil
+}
+
+// file formats every graph in the input, separated by blank lines.
+func (f *formatter) file() error {
+	if err := f.advance(); err != nil {
+		return err
+	}
+
+	for i := 0; f.tok.Type != TokenEOF; i++ {
+		if i > 0 {
+			f.out.WriteString("\n")
+		}
+		f.writeComments()
+		if err := f.graph(); err != nil {
+			return err
+		}
+	}
+	f.writeComments()
+
+	return nil
+}
+
+// graph formats [strict] (graph|digraph) [ID] { stmt_list }.
+func (f *formatter) graph() error {
+	var header string
+	if f.isKeyword("strict") {
+		header = "strict "
+		if err := f.skip(); err != nil {
+			return err
+		}
+	}
+
+	if !f.isKeyword("graph", "digraph") {
+		return errors.New("expected 'graph' or 'digraph'")
+	}
+	header += strings.ToLower(f.raw)
+	if err := f.skip(); err != nil {
+		return err
+	}
+
+	if f.isID() {
+		id, err := f.accept()
+		if err != nil {
+			return err
+		}
+		header += " " + id
+	}
+
+	return f.block(header + " {")
+}
+
+// block formats { stmt_list } after writing header, which ends with the opening brace.
+// The closing brace is left for the caller to write, so it can continue the line.
+func (f *formatter) block(header string) error {
+	if err := f.expect(TokenLBrace); err != nil {
+		return err
+	}
+	f.endLine(header)
+
+	f.depth++
+	err := f.stmtList()
+	f.depth--
+	if err != nil {
+		return err
+	}
+
+	if err := f.expect(TokenRBrace); err != nil {
+		return err
+	}
+	if f.depth == 0 {
+		f.endLine("}")
+	}
+
+	return nil
+}
+
+// stmtList formats statements up to the closing brace of the enclosing block.
+func (f *formatter) stmtList() error {
+	for f.tok.Type != TokenRBrace && f.tok.Type != TokenEOF {
+		f.writeComments()
+
+		// Stray semicolons are dropped
+		if f.tok.Type == TokenSemi {
+			if err := f.skip(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := f.stmt(); err != nil {
+			return err
+		}
+	}
+	f.writeComments()
+
+	return nil
+}
+
+// stmt formats a single statement: an attribute statement, an ID=ID assignment, a
+// node statement, an edge statement or a subgraph.
+func (f *formatter) stmt() error {
+	var line string
+	var err error
+
+	if f.isKeyword("node", "edge", "graph") {
+		line = strings.ToLower(f.raw)
+		if err = f.skip(); err != nil {
+			return err
+		}
+		if line, err = f.attrLists(line); err != nil {
+			return err
+		}
+		return f.endStmt(line + ";")
+	}
+
+	isSubgraph := f.isKeyword("subgraph") || f.tok.Type == TokenLBrace
+	if line, err = f.endpoint(""); err != nil {
+		return err
+	}
+
+	if f.tok.Type == TokenEqual && !isSubgraph {
+		if err = f.skip(); err != nil {
+			return err
+		}
+		value, err := f.accept()
+		if err != nil {
+			return err
+		}
+		return f.endStmt(line + "=" + value + ";")
+	}
+
+	hasArrow := f.tok.Type == TokenArrow
+	for f.tok.Type == TokenArrow {
+		line += " " + f.raw + " "
+		if err = f.skip(); err != nil {
+			return err
+		}
+		if line, err = f.endpoint(line); err != nil {
+			return err
+		}
+	}
+
+	if line, err = f.attrLists(line); err != nil {
+		return err
+	}
+
+	// A subgraph on its own closes with a bare brace
+	if isSubgraph && !hasArrow {
+		return f.endStmt(line)
+	}
+	return f.endStmt(line + ";")
+}
+
+// endpoint appends a node ID with optional port and compass point, or a subgraph,
+// to line. A subgraph's header and body are written out, leaving its closing brace.
+func (f *formatter) endpoint(line string) (string, error) {
+	if f.isKeyword("subgraph") || f.tok.Type == TokenLBrace {
+		return f.subgraph(line)
+	}
+
+	id, err := f.accept()
+	if err != nil {
+		return "", err
+	}
+	line += id
+
+	for range 2 {
+		if f.tok.Type != TokenColon {
+			break
+		}
+		if err := f.skip(); err != nil {
+			return "", err
+		}
+		part, err := f.accept()
+		if err != nil {
+			return "", err
+		}
+		line += ":" + part
+	}
+
+	return line, nil
+}
+
+// subgraph formats [subgraph [ID]] { stmt_list }, with line as the start of its header.
+// Returns the closing brace as the start of the next line.
+func (f *formatter) subgraph(line string) (string, error) {
+	if f.isKeyword("subgraph") {
+		line += "subgraph "
+		if err := f.skip(); err != nil {
+			return "", err
+		}
+		if f.isID() {
+			id, err := f.accept()
+			if err != nil {
+				return "", err
+			}
+			line += id + " "
+		}
+	}
+
+	if err := f.block(line + "{"); err != nil {
+		return "", err
+	}
+
+	return "}", nil
+}
+
+// attrLists appends any attribute lists that follow to line, keeping their order.
+func (f *formatter) attrLists(line string) (string, error) {
+	for f.tok.Type == TokenLBracket {
+		if err := f.skip(); err != nil {
+			return "", err
+		}
+
+		var attrs []string
+		for f.tok.Type != TokenRBracket {
+			attr, err := f.attr()
+			if err != nil {
+				return "", err
+			}
+			attrs = append(attrs, attr)
+
+			if f.tok.Type == TokenComma || f.tok.Type == TokenSemi {
+				if err := f.skip(); err != nil {
+					return "", err
+				}
+			}
+		}
+		if err := f.skip(); err != nil {
+			return "", err
+		}
+
+		line += " " + f.attrListText(attrs)
+	}
+
+	return line, nil
+}
+
+// attr formats a single ID=ID pair within an attribute list.
+func (f *formatter) attr() (string, error) {
+	name, err := f.accept()
+	if err != nil {
+		return "", err
+	}
+	if err := f.expect(TokenEqual); err != nil {
+		return "", err
+	}
+	value, err := f.accept()
+	if err != nil {
+		return "", err
+	}
+
+	return name + "=" + value, nil
+}
+
+// attrListText returns a bracketed attribute list, on one line or one attribute per line.
+func (f *formatter) attrListText(attrs []string) string {
+	if len(attrs) == 0 || !f.attrPerLine {
+		return "[" + strings.Join(attrs, ", ") + "]"
+	}
+
+	inner := "\n" + strings.Repeat(f.indent, f.depth+1)
+	return "[" + inner + strings.Join(attrs, ","+inner) + "\n" + strings.Repeat(f.indent, f.depth) + "]"
+}
+
+// advance moves to the next token that is not a comment, collecting the comments
+// passed over. Characters that start no token are reported as errors.
+func (f *formatter) advance() error {
+	f.prevLine = f.lexer.line
+	f.comments = nil
+
+	for {
+		tok := f.lexer.Next()
+		raw := f.lexer.input[f.lexer.tokStart:f.lexer.pos]
+
+		switch {
+		case tok.Type == TokenComment:
+			f.comments = append(f.comments, formatComment{text: raw, line: tok.Line})
+			continue
+		case tok.Type == TokenEOF && tok.Value != "":
+			f.tok = tok
+			return fmt.Errorf("unexpected character %q", tok.Value)
+		case tok.Type == TokenString && (len(raw) < 2 || !strings.HasSuffix(raw, `"`)):
+			f.tok = tok
+			return errors.New("unterminated string")
+		}
+
+		f.tok, f.raw = tok, raw
+		return nil
+	}
+}
+
+// skip consumes the current token. Comments before it are part of the current
+// statement, so they are held back and written before it.
+func (f *formatter) skip() error {
+	f.pending = append(f.pending, f.comments...)
+	return f.advance()
+}
+
+// accept consumes the current token, which must be an ID, and returns its source text.
+func (f *formatter) accept() (string, error) {
+	if !f.isID() {
+		return "", fmt.Errorf("expected ID, got %s", f.tok.Type)
+	}
+
+	raw := f.raw
+	return raw, f.skip()
+}
+
+// expect consumes the current token, which must be of the given type.
+func (f *formatter) expect(expected TokenType) error {
+	if f.tok.Type != expected {
+		return fmt.Errorf("expected %s, got %s", expected, f.tok.Type)
+	}
+
+	return f.skip()
+}
+
+// isID reports whether the current token is an ID: an identifier, number, quoted string or HTML string.
+func (f *formatter) isID() bool {
+	switch f.tok.Type {
+	case TokenIdent, TokenNumber, TokenString, TokenHTML:
+		return true
+	default:
+		return false
+	}
+}
+
+// isKeyword reports whether the current token is one of the given keywords,
+// which DOT matches case-insensitively.
+func (f *formatter) isKeyword(keywords ...string) bool {
+	if f.tok.Type != TokenIdent {
+		return false
+	}
+
+	for _, keyword := range keywords {
+		if strings.EqualFold(f.raw, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// endStmt consumes the semicolon that may end a statement and writes the statement's
+// last line, so that a comment after the semicolon stays on the same line.
+func (f *formatter) endStmt(line string) error {
+	if f.tok.Type == TokenSemi {
+		if err := f.skip(); err != nil {
+			return err
+		}
+	}
+
+	f.endLine(line)
+	return nil
+}
+
+// endLine writes line at the current depth. Comments met within the statement are
+// written on their own lines first, and comments that follow the line's last token
+// on the same source line are appended to it.
+func (f *formatter) endLine(line string) {
+	f.writeCommentLines(f.pending)
+	f.pending = nil
+
+	var leading []formatComment
+	for _, c := range f.comments {
+		if c.line == f.prevLine && !strings.HasPrefix(c.text, "#") {
+			line += " " + c.text
+		} else {
+			leading = append(leading, c)
+		}
+	}
+	f.comments = leading
+
+	f.writeLine(line)
+}
+
+// writeComments writes the comments before the current token on their own lines.
+func (f *formatter) writeComments() {
+	f.writeCommentLines(f.comments)
+	f.comments = nil
+}
+
+// writeCommentLines writes each comment on its own line. Preprocessor lines are not
+// indented, since they must start at the beginning of a line.
+func (f *formatter) writeCommentLines(comments []formatComment) {
+	for _, c := range comments {
+		if strings.HasPrefix(c.text, "#") {
+			f.out.WriteString(strings.TrimRight(c.text, " \t\r") + "\n")
+			continue
+		}
+		f.writeLine(c.text)
+	}
+}
+
+// writeLine writes one line of output indented to the current depth.
+func (f *formatter) writeLine(line string) {
+	f.out.WriteString(strings.Repeat(f.indent, f.depth))
+	f.out.WriteString(line)
+	f.out.WriteString("\n")
+}
@@ -0,0 +1,114 @@
+// ABOUTME: Tests for FormatDOT, the DOT source formatter.
+// ABOUTME: Verifies layout, preservation of comments and source text, options, idempotence and errors.
+package goraffe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatDOT_Layout(t *testing.T) {
+	t.Run("writes one statement per line", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		out, err := FormatDOT([]byte(`Digraph G{rankdir=LR node[shape=box]a->b->c[label="x"];;c}`))
+
+		asrt.NoError(err)
+		asrt.Equal("digraph G {\n\trankdir=LR;\n\tnode [shape=box];\n\ta -> b -> c [label=\"x\"];\n\tc;\n}\n",
+			string(out))
+	})
+
+	t.Run("indents subgraphs and keeps ports", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		out, err := FormatDOT([]byte(`graph { subgraph cluster_0 { x } {a b} -- d:p:n }`))
+
+		asrt.NoError(err)
+		asrt.Equal("graph {\n\tsubgraph cluster_0 {\n\t\tx;\n\t}\n\t{\n\t\ta;\n\t\tb;\n\t} -- d:p:n;\n}\n", string(out))
+	})
+
+	t.Run("formats every graph in a file", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		out, err := FormatDOT([]byte("digraph { a }\nstrict graph g { b }"))
+
+		asrt.NoError(err)
+		asrt.Equal("digraph {\n\ta;\n}\n\nstrict graph g {\n\tb;\n}\n", string(out))
+	})
+}
+
+func TestFormatDOT_Preserves(t *testing.T) {
+	t.Run("keeps IDs, quoting and attribute order as written", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		out, err := FormatDOT([]byte(`digraph { "a" [z=1, label=<<b>x</b>>] [color="red"] }`))
+
+		asrt.NoError(err)
+		asrt.Equal("digraph {\n\t\"a\" [z=1, label=<<b>x</b>>] [color=\"red\"];\n}\n", string(out))
+	})
+
+	t.Run("keeps comments and preprocessor lines", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		src := "#line 1 \"deps.dot\"\n// generated\ndigraph { // open\n  /* block */\n" +
+			"  a -> b; // trailing\n  b -> /* inner */ c\n}\n"
+
+		out, err := FormatDOT([]byte(src))
+
+		asrt.NoError(err)
+		asrt.Equal("#line 1 \"deps.dot\"\n// generated\ndigraph { // open\n\t/* block */\n"+
+			"\ta -> b; // trailing\n\t/* inner */\n\tb -> c;\n}\n", string(out))
+	})
+
+	t.Run("is idempotent", func(t *testing.T) {
+		req := require.New(t)
+
+		src := "# 1\n/* a */ strict digraph G { node [shape=box] // n\n subgraph { x -> y } -> z [w=1]\n}"
+
+		once, err := FormatDOT([]byte(src))
+		req.NoError(err)
+		twice, err := FormatDOT(once)
+		req.NoError(err)
+		req.Equal(string(once), string(twice))
+	})
+}
+
+func TestFormatDOT_Options(t *testing.T) {
+	t.Run("applies indent and attribute per line", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		out, err := FormatDOT([]byte(`digraph { { a [color=red, shape=box] } }`),
+			WithIndent("  "), WithAttributePerLine())
+
+		asrt.NoError(err)
+		asrt.Equal("digraph {\n  {\n    a [\n      color=red,\n      shape=box\n    ];\n  }\n}\n", string(out))
+	})
+}
+
+func TestFormatDOT_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing edge target", "digraph { a -> }"},
+		{"unexpected character", "digraph { a @ b }"},
+		{"unterminated string", `digraph { a [label="x }`},
+		{"unclosed graph", "digraph { a"},
+		{"missing graph keyword", "{ a }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asrt := assert.New(t)
+
+			out, err := FormatDOT([]byte(tt.input))
+
+			asrt.Nil(out)
+			var parseErr *ParseError
+			asrt.True(errors.As(err, &parseErr))
+		})
+	}
+}
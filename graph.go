@@ -4,13 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"sort"
-	"strings"
+	"slices"
 )
 
 // ErrNilNode is returned when a nil node is passed to a function that requires a non-nil node.
 var ErrNilNode = errors.New("node cannot be nil")
 
+// ErrNodeNotFound is returned when a node ID passed to a function is not part of the graph.
+var ErrNodeNotFound = errors.New("node not found in graph")
+
 // Graph represents a Graphviz graph structure that can contain nodes and edges.
 // A graph can be directed or undirected, and optionally strict (preventing duplicate edges).
 // Use NewGraph to create a new graph instance.
@@ -19,11 +21,14 @@ type Graph struct {
 	directed, strict bool
 	nodeOrder        []*Node
 	nodes            map[string]int
-	edges            []*Edge
+	edges            edgeList
+	outEdges         map[string][]*Edge
+	inEdges          map[string][]*Edge
 	subgraphs        []*Subgraph
 	attrs            *GraphAttributes
 	defaultNodeAttrs *NodeAttributes
 	defaultEdgeAttrs *EdgeAttributes
+	comment          string
 }
 
 // NewGraph creates a new graph with the specified options.
@@ -37,7 +42,8 @@ func NewGraph(options ...GraphOption) *Graph {
 	g := &Graph{
 		nodeOrder:        make([]*Node, 0),
 		nodes:            make(map[string]int),
-		edges:            make([]*Edge, 0),
+		outEdges:         make(map[string][]*Edge),
+		inEdges:          make(map[string][]*Edge),
 		subgraphs:        make([]*Subgraph, 0),
 		attrs:            &GraphAttributes{},
 		defaultNodeAttrs: &NodeAttributes{},
@@ -58,7 +64,7 @@ func (g *Graph) IsDirected() bool {
 }
 
 // IsStrict returns true if the graph is strict.
-// Strict graphs do not allow duplicate edges between the same pair of nodes.
+// Strict graphs do not allow duplicate edges between the same pair of nodes or self-loops.
 func (g *Graph) IsStrict() bool {
 	return g.strict
 }
@@ -109,6 +115,12 @@ func (g *Graph) Nodes() []*Node {
 // If either node is not already in the graph, it will be automatically added.
 // Returns the created edge and an error if either node is nil.
 //
+// In strict graphs, adding an edge between two nodes that are already connected merges
+// the new attributes into the existing edge (later values win) and returns the existing
+// edge. In undirected strict graphs A--B and B--A are the same edge. Self-loops are
+// silently dropped from strict graphs, as Graphviz does: the node is still added, but
+// no edge is, and AddEdge returns a nil edge and a nil error.
+//
 // Example:
 //
 //	n1 := goraffe.NewNode("A")
@@ -139,6 +151,30 @@ func (g *Graph) AddEdge(from, to *Node, options ...EdgeOption) (*Edge, error) {
 		attrs: attrs,
 	}
 
+	return g.insertEdge(edge)
+}
+
+// insertEdge adds a fully built edge to the graph, applying strict-graph semantics
+// and adding its endpoints if they are not already present.
+// Returns the edge now representing the connection, which is an existing edge when
+// a strict graph merges a duplicate, or nil when a strict graph drops a self-loop.
+func (g *Graph) insertEdge(edge *Edge) (*Edge, error) {
+	from, to := edge.from, edge.to
+
+	if g.strict {
+		if from.ID() == to.ID() {
+			if _, exists := g.nodes[from.ID()]; !exists {
+				return nil, g.AddNode(from)
+			}
+			return nil, nil
+		}
+
+		if existing := g.findEdge(from.ID(), to.ID()); existing != nil {
+			existing.attrs.merge(edge.attrs)
+			return existing, nil
+		}
+	}
+
 	if _, exists := g.nodes[from.ID()]; !exists {
 		err := g.AddNode(from)
 		if err != nil {
@@ -153,15 +189,106 @@ func (g *Graph) AddEdge(from, to *Node, options ...EdgeOption) (*Edge, error) {
 		}
 	}
 
-	g.edges = append(g.edges, edge)
+	g.addEdge(edge)
 
 	return edge, nil
 }
 
+// findEdge returns the first edge joining the nodes with the given IDs,
+// ignoring orientation in undirected graphs. Returns nil if there is none.
+func (g *Graph) findEdge(from, to string) *Edge {
+	if edges := g.EdgesBetween(from, to); len(edges) > 0 {
+		return edges[0]
+	}
+
+	return nil
+}
+
 // Edges returns all edges in the graph in insertion order.
 // The returned slice should not be modified.
 func (g *Graph) Edges() []*Edge {
-	return g.edges
+	return g.edges.slice()
+}
+
+// addEdge appends an edge to the graph and records it in the adjacency index,
+// without applying strict-graph semantics.
+func (g *Graph) addEdge(e *Edge) {
+	g.edges.add(e)
+	g.indexEdge(e)
+}
+
+// RemoveNode removes the node with the given ID from the graph, together with every
+// edge that starts or ends at it. The node is also removed from all subgraphs,
+// including nested ones. The insertion order of the remaining nodes and edges is preserved.
+// Returns false if no node with the given ID exists in the graph.
+//
+// Example:
+//
+//	g.RemoveNode("A")
+func (g *Graph) RemoveNode(id string) bool {
+	idx, exists := g.nodes[id]
+	if !exists {
+		return false
+	}
+
+	g.nodeOrder = slices.Delete(g.nodeOrder, idx, idx+1)
+	delete(g.nodes, id)
+
+	// Shift the indices of every node that followed the removed one
+	for i := idx; i < len(g.nodeOrder); i++ {
+		g.nodes[g.nodeOrder[i].ID()] = i
+	}
+
+	// The index holds every incident edge, so only those need to be visited
+	incident := slices.Concat(g.outEdges[id], g.inEdges[id])
+	g.removeEdges(incident)
+	delete(g.outEdges, id)
+	delete(g.inEdges, id)
+
+	for _, sg := range g.subgraphs {
+		sg.removeNode(id)
+	}
+
+	return true
+}
+
+// RemoveEdge removes the given edge from the graph and from any subgraph that contains it.
+// Edges are matched by identity, so e must be a value returned by AddEdge or Edges.
+// Returns false if the edge is not part of the graph.
+func (g *Graph) RemoveEdge(e *Edge) bool {
+	if e == nil {
+		return false
+	}
+
+	return g.removeEdges([]*Edge{e}) > 0
+}
+
+// RemoveEdgesBetween removes every edge from the node with ID from to the node with ID to.
+// In undirected graphs, edges in either orientation are removed.
+// Returns the number of edges removed.
+func (g *Graph) RemoveEdgesBetween(from, to string) int {
+	return g.removeEdges(g.EdgesBetween(from, to))
+}
+
+// removeEdges removes the given edges from the graph, its adjacency index and its
+// subgraphs, preserving the order of the remaining edges. Edges not in the graph, and
+// repeats, are ignored. Returns the number of edges removed.
+func (g *Graph) removeEdges(edges []*Edge) int {
+	removed := 0
+	for _, e := range edges {
+		if g.edges.remove(e) {
+			g.unindexEdge(e)
+			removed++
+		}
+	}
+
+	if removed > 0 {
+		for _, sg := range g.subgraphs {
+			sg.removeEdges(edges)
+		}
+	}
+
+	return removed
 }
 
 // Attrs returns the graph's attributes (label, rank direction, colors, etc.).
@@ -188,105 +315,31 @@ func (g *Graph) DefaultEdgeAttrs() *EdgeAttributes {
 // 1. Graph declaration
 // 2. Graph attributes
 // 3. Default node/edge attributes
-// 4. Subgraphs (each contains their nodes)
+// 4. Subgraphs (each contains their nodes and edges)
 // 5. Nodes not in any subgraph
-// 6. All edges
+// 6. Edges not in any subgraph
 // 7. Closing brace
+//
+// The output is stable: calling String on the same graph always returns the same text.
+// Subgraphs, nodes and edges appear in the order they were added, nested subgraphs
+// appear before the nodes of their parent, and attributes are sorted by name, except
+// that subgraph attributes keep the order their attribute type declares them in.
+// Use NewDOTPrinter to control indentation, quoting and attribute layout.
 func (g *Graph) String() string {
-	builder := strings.Builder{}
-
-	if g.strict {
-		builder.WriteString("strict ")
-	}
-
-	if g.directed {
-		builder.WriteString("digraph")
-	} else {
-		builder.WriteString("graph")
-	}
-
-	if g.name != "" {
-		builder.WriteString(fmt.Sprintf(" %s", quoteDOTID(g.name)))
-	}
-
-	builder.WriteString(" {\n")
-
-	g.addGraphAttributes(&builder)
-	g.addDefaultNodeAttributes(&builder)
-	g.addDefaultEdgeAttrbiutes(&builder)
-
-	// Output subgraphs with their nodes
-	for _, subgraph := range g.subgraphs {
-		g.renderSubgraph(&builder, subgraph, 1)
-	}
-
-	// Output nodes not in any subgraph
-	nodesInSubgraphs := g.collectNodesInSubgraphs()
-	for _, node := range g.nodeOrder {
-		if !nodesInSubgraphs[node.ID()] {
-			builder.WriteString(fmt.Sprintf("\t%s;\n", node))
-		}
-	}
-
-	// Output all edges
-	for _, edge := range g.edges {
-		builder.WriteString(fmt.Sprintf("\t%s;\n", edge.ToString(g.directed)))
-	}
-
-	builder.WriteString("}")
-
-	return builder.String()
+	return defaultPrinter.Print(g)
 }
 
 // WriteDOT writes the graph's DOT representation to the given writer.
-// This is a convenience method that writes the output of String() to a writer.
-// Returns any error encountered during writing.
+// Without options the output is the same as String(); options configure the
+// layout as for NewDOTPrinter. Returns any error encountered during writing.
 //
 // Example:
 //
 //	f, _ := os.Create("graph.dot")
 //	defer f.Close()
-//	g.WriteDOT(f)
-func (g *Graph) WriteDOT(w io.Writer) error {
-	_, err := w.Write([]byte(g.String()))
-
-	return err
-}
-
-func (g *Graph) addGraphAttributes(builder *strings.Builder) {
-	attrs := g.attrs.List()
-	var attrsStr string
-
-	if len(attrs) > 0 {
-		sort.Strings(attrs)
-		attrsStr = strings.Join(attrs, "\n")
-
-		fmt.Fprintf(builder, "%s\n", attrsStr)
-	}
-}
-
-func (g *Graph) addDefaultNodeAttributes(builder *strings.Builder) {
-	attrs := g.defaultNodeAttrs.List()
-	var attrsStr string
-
-	if len(attrs) > 0 {
-		sort.Strings(attrs)
-		attrsStr = "[" + strings.Join(attrs, ", ") + "]"
-
-		fmt.Fprintf(builder, "\tnode %s;\n", attrsStr)
-	}
-}
-
-func (g *Graph) addDefaultEdgeAttrbiutes(builder *strings.Builder) {
-	attrs := g.defaultEdgeAttrs.List()
-	var attrsStr string
-
-	if len(attrs) > 0 {
-		sort.Strings(attrs)
-		attrsStr = "[" + strings.Join(attrs, ", ") + "]"
-
-		fmt.Fprintf(builder, "\tedge %s;\n", attrsStr)
-	}
+//	g.WriteDOT(f, WithIndent("  "), WithMinimalQuoting())
+func (g *Graph) WriteDOT(w io.Writer, opts ...PrinterOption) error {
+	return NewDOTPrinter(opts...).Fprint(w, g)
 }
 
 // collectNodesInSubgraphs returns a set of all node IDs that are in any subgraph.
@@ -312,50 +365,6 @@ func (g *Graph) collectNodesInSubgraphs() map[string]bool {
 	return nodesInSubgraphs
 }
 
-// renderSubgraph renders a subgraph and its nested subgraphs to DOT format.
-// The depth parameter controls indentation level.
-func (g *Graph) renderSubgraph(builder *strings.Builder, sg *Subgraph, depth int) {
-	indent := strings.Repeat("\t", depth)
-
-	// Start subgraph declaration
-	if sg.name == "" {
-		fmt.Fprintf(builder, "%ssubgraph {\n", indent)
-	} else {
-		fmt.Fprintf(builder, "%ssubgraph %s {\n", indent, quoteDOTID(sg.name))
-	}
-
-	// Add subgraph attributes
-	if sg.attrs != nil {
-		attrs := sg.attrs.List()
-		if len(attrs) > 0 {
-			for _, attr := range attrs {
-				fmt.Fprintf(builder, "%s\t%s;\n", indent, attr)
-			}
-		}
-	}
-
-	// Recursively render nested subgraphs first
-	for _, nested := range sg.subgraphs {
-		g.renderSubgraph(builder, nested, depth+1)
-	}
-
-	// Add nodes that belong directly to this subgraph (not in nested subgraphs)
-	nodesInNested := make(map[string]bool)
-	for _, nested := range sg.subgraphs {
-		for id := range nested.nodes {
-			nodesInNested[id] = true
-		}
-	}
-
-	for _, node := range sg.nodes {
-		if !nodesInNested[node.ID()] {
-			fmt.Fprintf(builder, "%s\t%s;\n", indent, node)
-		}
-	}
-
-	fmt.Fprintf(builder, "%s}\n", indent)
-}
-
 // Subgraph creates a new subgraph with the given name and executes the provided function.
 // The function receives the created subgraph as a parameter, allowing for subgraph configuration.
 // Returns the created subgraph for further use.
@@ -369,8 +378,7 @@ func (g *Graph) renderSubgraph(builder *strings.Builder, sg *Subgraph, depth int
 func (g *Graph) Subgraph(name string, fn func(*Subgraph)) *Subgraph {
 	sg := &Subgraph{
 		name:      name,
-		nodes:     make(map[string]*Node),
-		edges:     make([]*Edge, 0),
+		nodes:     make(map[string]int),
 		parent:    g,
 		subgraphs: make([]*Subgraph, 0),
 	}
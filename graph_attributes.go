@@ -1,8 +1,8 @@
 package goraffe
 
 import (
-	"fmt"
 	"maps"
+	"strconv"
 )
 
 // RankDir specifies the direction of graph layout from rank to rank.
@@ -85,6 +85,14 @@ func (a *GraphAttributes) setCustom(key, value string) {
 	a.custom[key] = value
 }
 
+// clone returns a copy of the attributes with its own custom attribute map.
+func (a *GraphAttributes) clone() *GraphAttributes {
+	copied := *a
+	copied.custom = maps.Clone(a.custom)
+
+	return &copied
+}
+
 // Label returns the graph label. Returns empty string if unset.
 // Note: An empty string return value may indicate either an unset label or a label
 // explicitly set to empty string.
@@ -182,39 +190,48 @@ func (a *GraphAttributes) Compound() bool {
 	return *a.compound
 }
 
+// List returns a slice of DOT attribute statements for rendering, each indented by a
+// tab and terminated by a semicolon. Only attributes that have been explicitly set are included.
 func (a *GraphAttributes) List() []string {
-	attrs := []string{}
+	attrs := formatAttributes(a.attributes())
+	for i, attr := range attrs {
+		attrs[i] = "\t" + attr + ";"
+	}
+
+	return attrs
+}
+
+// attributes returns the attributes that have been explicitly set, typed attributes
+// first in declaration order followed by custom attributes sorted by name.
+func (a *GraphAttributes) attributes() []attribute {
+	attrs := []attribute{}
 	if a.bgColor != nil {
-		attrs = append(attrs, fmt.Sprintf("\tbgcolor=\"%s\";", escapeDOTString(a.BgColor())))
+		attrs = append(attrs, attribute{name: "bgcolor", value: a.BgColor()})
 	}
 	if a.compound != nil {
-		attrs = append(attrs, fmt.Sprintf("\tcompound=\"%t\";", a.Compound()))
+		attrs = append(attrs, attribute{name: "compound", value: strconv.FormatBool(a.Compound())})
 	}
 	if a.fontName != nil {
-		attrs = append(attrs, fmt.Sprintf("\tfontname=\"%s\";", escapeDOTString(a.FontName())))
+		attrs = append(attrs, attribute{name: "fontname", value: a.FontName()})
 	}
 	if a.fontSize != nil {
-		attrs = append(attrs, fmt.Sprintf("\tfontsize=\"%g\";", a.FontSize()))
+		attrs = append(attrs, attribute{name: "fontsize", value: formatFloat(a.FontSize())})
 	}
 	if a.label != nil {
-		attrs = append(attrs, fmt.Sprintf("\tlabel=\"%s\";", escapeDOTString(a.Label())))
+		attrs = append(attrs, attribute{name: "label", value: a.Label()})
 	}
 	if a.nodeSep != nil {
-		attrs = append(attrs, fmt.Sprintf("\tnodesep=\"%g\";", a.NodeSep()))
+		attrs = append(attrs, attribute{name: "nodesep", value: formatFloat(a.NodeSep())})
 	}
 	if a.rankDir != nil {
-		attrs = append(attrs, fmt.Sprintf("\trankdir=\"%s\";", escapeDOTString(string(a.RankDir()))))
+		attrs = append(attrs, attribute{name: "rankdir", value: string(a.RankDir())})
 	}
 	if a.rankSep != nil {
-		attrs = append(attrs, fmt.Sprintf("\tranksep=\"%g\";", a.RankSep()))
+		attrs = append(attrs, attribute{name: "ranksep", value: formatFloat(a.RankSep())})
 	}
 	if a.splines != nil {
-		attrs = append(attrs, fmt.Sprintf("\tsplines=\"%s\";", escapeDOTString(string(a.Splines()))))
+		attrs = append(attrs, attribute{name: "splines", value: string(a.Splines())})
 	}
 
-	for k, v := range a.custom {
-		attrs = append(attrs, fmt.Sprintf("\t%s=\"%s\";", k, escapeDOTString(v)))
-	}
-
-	return attrs
+	return appendCustom(attrs, a.custom)
 }
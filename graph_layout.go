@@ -0,0 +1,343 @@
+// ABOUTME: Runs a Graphviz layout and returns the computed geometry of the graph.
+// ABOUTME: Parses Graphviz JSON output into positions keyed by the graph's own nodes, edges and subgraphs.
+package goraffe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// layoutIDAttr is the attribute used to match Graphviz's output back to edges and subgraphs.
+const layoutIDAttr = "_goraffe_id"
+
+// pointsPerInch converts Graphviz node sizes, which are given in inches, to points.
+const pointsPerInch = 72
+
+// Point is a position in a Graphviz layout, in points.
+type Point struct {
+	X, Y float64
+}
+
+// Rect is an axis-aligned rectangle in a Graphviz layout, from its lower-left corner Min
+// to its upper-right corner Max.
+type Rect struct {
+	Min, Max Point
+}
+
+// NodeLayout is the computed position and size of a node.
+type NodeLayout struct {
+	Center Point   // Center of the node
+	Width  float64 // Width in points
+	Height float64 // Height in points
+}
+
+// Spline is a piecewise cubic Bézier curve drawn for an edge.
+type Spline struct {
+	// Points are the control points: a start point followed by three points per segment.
+	Points []Point
+	// Start is where the arrowhead at the tail ends, or nil if there is none.
+	Start *Point
+	// End is where the arrowhead at the head ends, or nil if there is none.
+	End *Point
+}
+
+// EdgeLayout is the computed route of an edge and the positions of its labels.
+// Label positions are nil for labels the edge does not have.
+type EdgeLayout struct {
+	Splines   []Spline
+	Label     *Point
+	HeadLabel *Point
+	TailLabel *Point
+}
+
+// SubgraphLayout is the computed extent of a cluster and the position of its label.
+type SubgraphLayout struct {
+	BoundingBox Rect
+	Label       *Point // Nil if the cluster has no label
+}
+
+// LayoutResult is the geometry Graphviz computed for a graph, keyed by the graph's own
+// nodes, edges and subgraphs. All coordinates are in points with the origin at the
+// lower left, as Graphviz reports them; flip Y against BoundingBox.Max.Y to draw on
+// a canvas whose origin is at the top left.
+type LayoutResult struct {
+	BoundingBox Rect
+	Nodes       map[*Node]NodeLayout
+	Edges       map[*Edge]EdgeLayout
+	// Subgraphs holds the clusters. Graphviz does not lay out non-cluster subgraphs
+	// as regions, so they have no entry.
+	Subgraphs map[*Subgraph]SubgraphLayout
+}
+
+// layoutJSON is the part of Graphviz's JSON output that describes the layout.
+type layoutJSON struct {
+	BB            string         `json:"bb"`
+	SubgraphCount int            `json:"_subgraph_cnt"`
+	Objects       []layoutObject `json:"objects"`
+	Edges         []layoutObject `json:"edges"`
+}
+
+// layoutObject is a subgraph, node or edge in Graphviz's JSON output. Graphviz writes
+// every attribute value as a string.
+type layoutObject struct {
+	Name   string `json:"name"`
+	ID     string `json:"_goraffe_id"`
+	BB     string `json:"bb"`
+	Pos    string `json:"pos"`
+	Width  string `json:"width"`
+	Height string `json:"height"`
+	LP     string `json:"lp"`
+	HeadLP string `json:"head_lp"`
+	TailLP string `json:"tail_lp"`
+}
+
+// Layout runs Graphviz and returns the position and size of every node, the route of
+// every edge and the extent of every cluster, for callers that draw the graph
+// themselves. Render options select the layout engine and timeout as for RenderContext,
+// and errors are reported the same way.
+//
+// Example:
+//
+//	result, err := g.Layout(ctx, goraffe.WithLayout(goraffe.LayoutNeato))
+//	if err != nil {
+//	    return err
+//	}
+//	pos := result.Nodes[n].Center
+func (g *Graph) Layout(ctx context.Context, opts ...RenderOption) (*LayoutResult, error) {
+	// Lay out a tagged copy so Graphviz's output can be matched back to g
+	tagged := g.Clone()
+	for i, e := range tagged.Edges() {
+		e.attrs.setCustom(layoutIDAttr, strconv.Itoa(i))
+	}
+	subgraphs := tagSubgraphs(tagged.subgraphs, g.subgraphs, nil)
+	for i, sg := range subgraphs {
+		sg.tagged.SetAttribute(layoutIDAttr, strconv.Itoa(i))
+	}
+
+	// JSON0 is the JSON output without drawing instructions, which are not needed here
+	output, err := tagged.RenderBytesContext(ctx, JSON0, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var data layoutJSON
+	if err := json.Unmarshal(output, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse Graphviz layout: %w", err)
+	}
+
+	result, err := g.layoutResult(&data, subgraphs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Graphviz layout: %w", err)
+	}
+	return result, nil
+}
+
+// taggedSubgraph pairs a subgraph of the tagged copy with the original it was cloned from.
+type taggedSubgraph struct {
+	tagged, original *Subgraph
+}
+
+// tagSubgraphs appends every subgraph in the parallel hierarchies to pairs, depth first.
+func tagSubgraphs(tagged, original []*Subgraph, pairs []taggedSubgraph) []taggedSubgraph {
+	for i := range tagged {
+		pairs = append(pairs, taggedSubgraph{tagged: tagged[i], original: original[i]})
+		pairs = tagSubgraphs(tagged[i].subgraphs, original[i].subgraphs, pairs)
+	}
+	return pairs
+}
+
+// layoutResult converts Graphviz's output into a LayoutResult keyed by g's elements.
+func (g *Graph) layoutResult(data *layoutJSON, subgraphs []taggedSubgraph) (*LayoutResult, error) {
+	result := &LayoutResult{
+		Nodes:     make(map[*Node]NodeLayout),
+		Edges:     make(map[*Edge]EdgeLayout),
+		Subgraphs: make(map[*Subgraph]SubgraphLayout),
+	}
+
+	var err error
+	if data.BB != "" {
+		if result.BoundingBox, err = parseRect(data.BB); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, obj := range data.Objects {
+		if i < data.SubgraphCount {
+			err = addSubgraphLayout(result, obj, subgraphs)
+		} else {
+			err = g.addNodeLayout(result, obj)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, obj := range data.Edges {
+		if err := g.addEdgeLayout(result, obj); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// addSubgraphLayout records the layout of a cluster. Other subgraphs have no bounding box.
+func addSubgraphLayout(result *LayoutResult, obj layoutObject, subgraphs []taggedSubgraph) error {
+	idx, convErr := strconv.Atoi(obj.ID)
+	if obj.BB == "" || convErr != nil || idx < 0 || idx >= len(subgraphs) {
+		return nil
+	}
+
+	var layout SubgraphLayout
+	var err error
+	if layout.BoundingBox, err = parseRect(obj.BB); err != nil {
+		return err
+	}
+	if layout.Label, err = parseOptionalPoint(obj.LP); err != nil {
+		return err
+	}
+
+	result.Subgraphs[subgraphs[idx].original] = layout
+	return nil
+}
+
+// addNodeLayout records the layout of the node with the object's name.
+func (g *Graph) addNodeLayout(result *LayoutResult, obj layoutObject) error {
+	n := g.GetNode(obj.Name)
+	if n == nil {
+		return nil
+	}
+
+	center, err := parsePoint(obj.Pos)
+	if err != nil {
+		return err
+	}
+	width, err := strconv.ParseFloat(obj.Width, 64)
+	if err != nil {
+		return fmt.Errorf("invalid width %q for node %q", obj.Width, obj.Name)
+	}
+	height, err := strconv.ParseFloat(obj.Height, 64)
+	if err != nil {
+		return fmt.Errorf("invalid height %q for node %q", obj.Height, obj.Name)
+	}
+
+	result.Nodes[n] = NodeLayout{Center: center, Width: width * pointsPerInch, Height: height * pointsPerInch}
+	return nil
+}
+
+// addEdgeLayout records the layout of the edge tagged with the object's ID.
+func (g *Graph) addEdgeLayout(result *LayoutResult, obj layoutObject) error {
+	idx, err := strconv.Atoi(obj.ID)
+	edges := g.Edges()
+	if err != nil || idx < 0 || idx >= len(edges) {
+		return fmt.Errorf("unknown edge %q", obj.ID)
+	}
+
+	var layout EdgeLayout
+	if layout.Splines, err = parseSplines(obj.Pos); err != nil {
+		return err
+	}
+	if layout.Label, err = parseOptionalPoint(obj.LP); err != nil {
+		return err
+	}
+	if layout.HeadLabel, err = parseOptionalPoint(obj.HeadLP); err != nil {
+		return err
+	}
+	if layout.TailLabel, err = parseOptionalPoint(obj.TailLP); err != nil {
+		return err
+	}
+
+	result.Edges[edges[idx]] = layout
+	return nil
+}
+
+// parseSplines parses an edge's pos attribute: splines separated by semicolons, each
+// a list of points optionally preceded by "s,x,y" and "e,x,y" arrowhead end points.
+func parseSplines(s string) ([]Spline, error) {
+	var splines []Spline
+
+	for spec := range strings.SplitSeq(s, ";") {
+		var spline Spline
+		for field := range strings.FieldsSeq(spec) {
+			kind, rest, _ := strings.Cut(field, ",")
+			var target **Point
+			switch kind {
+			case "s":
+				target = &spline.Start
+			case "e":
+				target = &spline.End
+			default:
+				rest = field
+			}
+
+			p, err := parsePoint(rest)
+			if err != nil {
+				return nil, err
+			}
+			if target != nil {
+				*target = &p
+			} else {
+				spline.Points = append(spline.Points, p)
+			}
+		}
+
+		if len(spline.Points) > 0 {
+			splines = append(splines, spline)
+		}
+	}
+
+	return splines, nil
+}
+
+// parsePoint parses an "x,y" position.
+func parsePoint(s string) (Point, error) {
+	values, err := parseFloats(s, 2)
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{X: values[0], Y: values[1]}, nil
+}
+
+// parseOptionalPoint parses an "x,y" position, returning nil if s is empty.
+func parseOptionalPoint(s string) (*Point, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	p, err := parsePoint(s)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// parseRect parses an "llx,lly,urx,ury" bounding box.
+func parseRect(s string) (Rect, error) {
+	values, err := parseFloats(s, 4)
+	if err != nil {
+		return Rect{}, err
+	}
+	return Rect{Min: Point{X: values[0], Y: values[1]}, Max: Point{X: values[2], Y: values[3]}}, nil
+}
+
+// parseFloats parses exactly count comma-separated numbers.
+func parseFloats(s string, count int) ([]float64, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != count {
+		return nil, fmt.Errorf("expected %d comma-separated numbers, got %q", count, s)
+	}
+
+	values := make([]float64, count)
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected %d comma-separated numbers, got %q", count, s)
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
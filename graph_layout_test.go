@@ -0,0 +1,165 @@
+// ABOUTME: Tests for Graph.Layout and parsing of Graphviz layout output.
+// ABOUTME: Uses a fake dot script that returns canned JSON, plus a real Graphviz run when available.
+package goraffe
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// layoutFixture is Graphviz JSON output for layoutTestGraph, trimmed to the fields Layout reads.
+const layoutFixture = `{
+  "name": "G", "directed": true, "bb": "0,0,116,196", "_subgraph_cnt": 2,
+  "objects": [
+    {"_gvid": 0, "name": "cluster_0", "_goraffe_id": "0", "bb": "8,80,98,188", "lp": "53,176.5", "label": "group"},
+    {"_gvid": 1, "name": "%2", "_goraffe_id": "1"},
+    {"_gvid": 2, "name": "A", "pos": "63,150", "width": "0.75", "height": "0.5"},
+    {"_gvid": 3, "name": "B", "pos": "63,106", "width": "1.5", "height": "0.5"},
+    {"_gvid": 4, "name": "C", "pos": "63,18", "width": "0.75", "height": "0.5"}
+  ],
+  "edges": [
+    {"_gvid": 1, "tail": 3, "head": 4, "_goraffe_id": "1", "pos": "e,63,36.1 63,87.6 63,75.6 63,59.6 63,46.1"},
+    {"_gvid": 0, "tail": 2, "head": 3, "_goraffe_id": "0", "pos": "e,63,124.1 63,131.7 63,129 63,126.9 63,124.1",
+     "lp": "70,128", "head_lp": "58,120"}
+  ]
+}`
+
+// layoutTestGraph builds a graph with a cluster, a plain subgraph and a labelled edge.
+func layoutTestGraph() (g *Graph, a, b, c *Node) {
+	g = NewGraph(Directed, WithName("G"))
+	a, b, c = NewNode("A"), NewNode("B"), NewNode("C")
+	g.Subgraph("cluster_0", func(s *Subgraph) {
+		s.SetLabel("group")
+		_, _ = s.AddEdge(a, b, WithEdgeLabel("x"))
+	})
+	g.Subgraph("", func(s *Subgraph) {
+		_ = s.AddNode(c)
+	})
+	_, _ = g.AddEdge(b, c)
+
+	return g, a, b, c
+}
+
+// fakeGraphvizLayout fakes a Graphviz that prints output as its layout and saves the DOT
+// it was given to the returned path.
+func fakeGraphvizLayout(t *testing.T, output string) string {
+	t.Helper()
+	input := filepath.Join(t.TempDir(), "input.dot")
+	fakeGraphviz(t, `if [ "$1" = "-T?" ]; then
+  echo 'Format: "?" not recognized. Use one of: dot json json0 svg' >&2
+  exit 1
+fi
+cat > '`+input+`'
+cat <<'EOF'
+`+output+`
+EOF`)
+
+	return input
+}
+
+func TestGraph_Layout(t *testing.T) {
+	t.Run("keys the layout by the graph's own elements", func(t *testing.T) {
+		req := require.New(t)
+		fakeGraphvizLayout(t, layoutFixture)
+
+		g, a, b, c := layoutTestGraph()
+		ab, bc := g.Edges()[0], g.Edges()[1]
+
+		result, err := g.Layout(context.Background())
+
+		req.NoError(err)
+		req.Equal(Rect{Min: Point{0, 0}, Max: Point{116, 196}}, result.BoundingBox)
+
+		req.Len(result.Nodes, 3)
+		req.Equal(NodeLayout{Center: Point{63, 150}, Width: 54, Height: 36}, result.Nodes[a])
+		req.Equal(NodeLayout{Center: Point{63, 106}, Width: 108, Height: 36}, result.Nodes[b])
+		req.Equal(Point{63, 18}, result.Nodes[c].Center)
+
+		req.Len(result.Edges, 2)
+		req.Equal(EdgeLayout{
+			Splines: []Spline{{
+				Points: []Point{{63, 131.7}, {63, 129}, {63, 126.9}, {63, 124.1}},
+				End:    &Point{63, 124.1},
+			}},
+			Label:     &Point{70, 128},
+			HeadLabel: &Point{58, 120},
+		}, result.Edges[ab])
+		req.Nil(result.Edges[bc].Label)
+		req.Len(result.Edges[bc].Splines[0].Points, 4)
+
+		req.Equal(map[*Subgraph]SubgraphLayout{
+			g.Subgraphs()[0]: {BoundingBox: Rect{Min: Point{8, 80}, Max: Point{98, 188}}, Label: &Point{53, 176.5}},
+		}, result.Subgraphs)
+	})
+
+	t.Run("tags a copy of the graph, leaving it unchanged", func(t *testing.T) {
+		req := require.New(t)
+		input := fakeGraphvizLayout(t, layoutFixture)
+
+		g, _, _, _ := layoutTestGraph()
+		before := g.String()
+
+		_, err := g.Layout(context.Background())
+
+		req.NoError(err)
+		req.Equal(before, g.String())
+		sent, err := os.ReadFile(input)
+		req.NoError(err)
+		req.Contains(string(sent), `_goraffe_id="0"`)
+		req.Contains(string(sent), `_goraffe_id="1"`)
+	})
+
+	t.Run("reports unparseable output", func(t *testing.T) {
+		asrt := assert.New(t)
+		fakeGraphvizLayout(t, `{"bb": "0,0", "objects": []}`)
+
+		g, _, _, _ := layoutTestGraph()
+		result, err := g.Layout(context.Background())
+
+		asrt.Nil(result)
+		asrt.ErrorContains(err, "failed to parse Graphviz layout")
+	})
+
+	t.Run("lays out with a real Graphviz", func(t *testing.T) {
+		requireGraphviz(t)
+		req := require.New(t)
+
+		g, a, _, _ := layoutTestGraph()
+
+		result, err := g.Layout(context.Background(), WithLayout(LayoutDot))
+
+		req.NoError(err)
+		req.Len(result.Nodes, 3)
+		req.Len(result.Edges, 2)
+		req.Len(result.Subgraphs, 1)
+		req.Positive(result.Nodes[a].Width)
+		req.NotNil(result.Edges[g.Edges()[0]].Label)
+	})
+}
+
+func TestParseSplines(t *testing.T) {
+	t.Run("parses arrowhead points and multiple splines", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		splines, err := parseSplines("s,1,2 e,9,9 1,3 2,4 3,5 4,6;5,5 6,6 7,7 8,8")
+
+		asrt.NoError(err)
+		asrt.Equal([]Spline{
+			{Points: []Point{{1, 3}, {2, 4}, {3, 5}, {4, 6}}, Start: &Point{1, 2}, End: &Point{9, 9}},
+			{Points: []Point{{5, 5}, {6, 6}, {7, 7}, {8, 8}}},
+		}, splines)
+	})
+
+	t.Run("rejects malformed points", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		_, err := parseSplines("1,2 3")
+
+		asrt.Error(err)
+	})
+}
@@ -33,7 +33,8 @@ var Undirected GraphOption = graphOptionFunc(func(g *Graph) {
 })
 
 // Strict prevents duplicate edges between the same pair of nodes.
-// In strict graphs, only one edge is allowed between any two nodes.
+// In strict graphs, only one edge is allowed between any two nodes: adding another
+// merges its attributes into the existing edge, and self-loops are dropped.
 //
 // Example:
 //
@@ -1,9 +1,11 @@
 package goraffe
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewGraph_DefaultValues(t *testing.T) {
@@ -626,3 +628,287 @@ func TestGraph_RankMethods_ErrorOnNilNode(t *testing.T) {
 		asrt.Len(g.Nodes(), 0, "expected no nodes added when rank method fails")
 	})
 }
+
+func TestGraph_RemoveNode(t *testing.T) {
+	t.Run("removes node and preserves order", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		a, b, c := NewNode("A"), NewNode("B"), NewNode("C")
+		_ = g.AddNode(a)
+		_ = g.AddNode(b)
+		_ = g.AddNode(c)
+
+		removed := g.RemoveNode("B")
+
+		asrt.True(removed, "expected RemoveNode to report removal")
+		asrt.Equal([]*Node{a, c}, g.Nodes(), "expected remaining nodes in insertion order")
+		asrt.Nil(g.GetNode("B"), "expected removed node to be gone")
+		asrt.Same(c, g.GetNode("C"), "expected index of later nodes to be updated")
+	})
+
+	t.Run("returns false for unknown node", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		_ = g.AddNode(NewNode("A"))
+
+		asrt.False(g.RemoveNode("Z"), "expected RemoveNode to report no removal")
+		asrt.Len(g.Nodes(), 1, "expected graph to be unchanged")
+	})
+
+	t.Run("removes incident edges", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		a, b, c := NewNode("A"), NewNode("B"), NewNode("C")
+		_, _ = g.AddEdge(a, b)
+		e2, _ := g.AddEdge(a, c)
+		_, _ = g.AddEdge(c, b)
+
+		g.RemoveNode("B")
+
+		asrt.Equal([]*Edge{e2}, g.Edges(), "expected only edges not touching B to remain")
+	})
+
+	t.Run("purges node from nested subgraphs", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		var inner *Subgraph
+		outer := g.Subgraph("cluster_outer", func(o *Subgraph) {
+			_ = o.AddNode(NewNode("A"))
+			inner = o.Subgraph("cluster_inner", func(i *Subgraph) {
+				_, _ = i.AddEdge(NewNode("B"), NewNode("C"))
+			})
+		})
+
+		g.RemoveNode("B")
+
+		asrt.Len(outer.Nodes(), 1, "expected outer subgraph to keep A")
+		asrt.Len(inner.Nodes(), 1, "expected inner subgraph to lose B")
+		asrt.Equal("C", inner.Nodes()[0].ID(), "expected inner subgraph to keep C")
+		asrt.Empty(inner.Edges(), "expected inner subgraph edge to be removed")
+		asrt.NotContains(g.String(), `"B"`, "expected B to be absent from DOT output")
+	})
+
+	t.Run("removes self-loops", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		a, b := NewNode("A"), NewNode("B")
+		_, _ = g.AddEdge(a, a)
+		keep, _ := g.AddEdge(b, b)
+
+		g.RemoveNode("A")
+
+		asrt.Equal([]*Edge{keep}, g.Edges(), "expected only B's self-loop to remain")
+		asrt.Equal([]*Edge{keep}, g.EdgesBetween("B", "B"), "expected adjacency index to be intact")
+	})
+
+	t.Run("keeps edge order while pruning many nodes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := chainGraph(100)
+		for i := 0; i < 100; i += 2 {
+			g.RemoveNode("N" + strconv.Itoa(i))
+		}
+
+		asrt.Empty(g.Edges(), "expected every edge to touch a removed node")
+
+		g = chainGraph(100)
+		for i := 0; i < 90; i++ {
+			g.RemoveNode("N" + strconv.Itoa(i))
+		}
+
+		edges := g.Edges()
+		req := require.New(t)
+		req.Len(edges, 9, "expected the tail of the chain to remain")
+		for i, e := range edges {
+			asrt.Equal("N"+strconv.Itoa(90+i), e.From().ID(), "expected remaining edges in insertion order")
+		}
+	})
+}
+
+func TestGraph_RemoveEdge(t *testing.T) {
+	t.Run("removes only the given edge", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		a, b := NewNode("A"), NewNode("B")
+		e1, _ := g.AddEdge(a, b)
+		e2, _ := g.AddEdge(a, b)
+		e3, _ := g.AddEdge(b, a)
+
+		asrt.True(g.RemoveEdge(e2), "expected RemoveEdge to report removal")
+		asrt.Equal([]*Edge{e1, e3}, g.Edges(), "expected remaining edges in insertion order")
+		asrt.Len(g.Nodes(), 2, "expected nodes to be untouched")
+	})
+
+	t.Run("returns false for edges not in graph", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		other := NewGraph()
+		e, _ := other.AddEdge(NewNode("A"), NewNode("B"))
+
+		asrt.False(g.RemoveEdge(e), "expected no removal for foreign edge")
+		asrt.False(g.RemoveEdge(nil), "expected no removal for nil edge")
+	})
+
+	t.Run("removes edge from subgraph", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		var e *Edge
+		sg := g.Subgraph("cluster_0", func(s *Subgraph) {
+			e, _ = s.AddEdge(NewNode("A"), NewNode("B"))
+		})
+
+		g.RemoveEdge(e)
+
+		asrt.Empty(g.Edges(), "expected edge removed from graph")
+		asrt.Empty(sg.Edges(), "expected edge removed from subgraph")
+	})
+}
+
+func TestGraph_RemoveEdgesBetween(t *testing.T) {
+	t.Run("directed graph respects orientation", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		a, b := NewNode("A"), NewNode("B")
+		_, _ = g.AddEdge(a, b)
+		_, _ = g.AddEdge(a, b)
+		back, _ := g.AddEdge(b, a)
+
+		removed := g.RemoveEdgesBetween("A", "B")
+
+		asrt.Equal(2, removed, "expected both A->B edges to be removed")
+		asrt.Equal([]*Edge{back}, g.Edges(), "expected B->A edge to remain")
+	})
+
+	t.Run("undirected graph ignores orientation", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Undirected)
+		a, b, c := NewNode("A"), NewNode("B"), NewNode("C")
+		_, _ = g.AddEdge(a, b)
+		_, _ = g.AddEdge(b, a)
+		keep, _ := g.AddEdge(a, c)
+
+		removed := g.RemoveEdgesBetween("A", "B")
+
+		asrt.Equal(2, removed, "expected edges in both orientations to be removed")
+		asrt.Equal([]*Edge{keep}, g.Edges(), "expected unrelated edge to remain")
+	})
+}
+
+func BenchmarkGraph_RemoveEdgesBetween(b *testing.B) {
+	for b.Loop() {
+		b.StopTimer()
+		g := chainGraph(50000)
+		b.StartTimer()
+
+		for i := range 1000 {
+			g.RemoveEdgesBetween("N"+strconv.Itoa(i), "N"+strconv.Itoa(i+1))
+		}
+	}
+}
+
+// chainGraph builds a directed path N0->N1->...->N(n-1).
+func chainGraph(n int) *Graph {
+	g := NewGraph(Directed)
+	for i := 1; i < n; i++ {
+		from := g.GetNode("N" + strconv.Itoa(i-1))
+		if from == nil {
+			from = NewNode("N" + strconv.Itoa(i-1))
+		}
+		_, _ = g.AddEdge(from, NewNode("N"+strconv.Itoa(i)))
+	}
+
+	return g
+}
+
+func TestGraph_AddEdge_Strict(t *testing.T) {
+	t.Run("merges duplicate directed edges", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed, Strict)
+		a, b := NewNode("A"), NewNode("B")
+		e1, _ := g.AddEdge(a, b, WithEdgeLabel("first"), WithEdgeColor("red"))
+		e2, err := g.AddEdge(a, b, WithEdgeLabel("second"), WithEdgeAttribute("penwidth", "2"))
+
+		asrt.NoError(err, "expected no error when merging duplicate edge")
+		asrt.Same(e1, e2, "expected AddEdge to return the existing edge")
+		asrt.Len(g.Edges(), 1, "expected a single edge")
+		asrt.Equal("second", e1.Attrs().Label(), "expected later label to win")
+		asrt.Equal("red", e1.Attrs().Color(), "expected earlier attributes to be kept")
+		asrt.Equal("2", e1.Attrs().Custom()["penwidth"], "expected custom attributes to merge")
+	})
+
+	t.Run("keeps reverse edges distinct in directed graphs", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed, Strict)
+		a, b := NewNode("A"), NewNode("B")
+		e1, _ := g.AddEdge(a, b)
+		e2, _ := g.AddEdge(b, a)
+
+		asrt.NotSame(e1, e2, "expected B->A to be a separate edge")
+		asrt.Len(g.Edges(), 2, "expected two edges")
+	})
+
+	t.Run("treats reverse edges as duplicates in undirected graphs", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Undirected, Strict)
+		a, b := NewNode("A"), NewNode("B")
+		e1, _ := g.AddEdge(a, b)
+		e2, _ := g.AddEdge(b, a, WithEdgeColor("blue"))
+
+		asrt.Same(e1, e2, "expected B--A to merge into A--B")
+		asrt.Len(g.Edges(), 1, "expected a single edge")
+		asrt.Equal("A", e1.From().ID(), "expected original orientation to be kept")
+		asrt.Equal("blue", e1.Attrs().Color(), "expected attributes to merge")
+	})
+
+	t.Run("drops self-loops", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed, Strict)
+		a := NewNode("A")
+		e, err := g.AddEdge(a, a)
+
+		asrt.NoError(err, "expected self-loop to be dropped silently")
+		asrt.Nil(e, "expected no edge to be returned")
+		asrt.Empty(g.Edges(), "expected self-loop to be dropped")
+		asrt.Same(a, g.GetNode("A"), "expected the node to still be added")
+	})
+
+	t.Run("non-strict graphs keep duplicates and self-loops", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		a, b := NewNode("A"), NewNode("B")
+		_, _ = g.AddEdge(a, b)
+		_, _ = g.AddEdge(a, b)
+		_, err := g.AddEdge(a, a)
+
+		asrt.NoError(err, "expected self-loop to be allowed")
+		asrt.Len(g.Edges(), 3, "expected all edges to be kept")
+	})
+
+	t.Run("subgraph does not record merged edge twice", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed, Strict)
+		sg := g.Subgraph("cluster_0", func(s *Subgraph) {
+			_, _ = s.AddEdge(NewNode("A"), NewNode("B"))
+			_, _ = s.AddEdge(NewNode("A"), NewNode("B"))
+		})
+
+		asrt.Len(sg.Edges(), 1, "expected a single edge in the subgraph")
+		asrt.Len(g.Edges(), 1, "expected a single edge in the graph")
+	})
+}
@@ -0,0 +1,396 @@
+// ABOUTME: Parses the body of DOT HTML-like labels into HTMLLabel table trees.
+// ABOUTME: Markup outside the subset HTMLLabel can represent is reported as unsupported.
+package goraffe
+
+import (
+	"strconv"
+	"strings"
+)
+
+// htmlTokenKind identifies the kind of an htmlToken.
+type htmlTokenKind int
+
+const (
+	htmlText      htmlTokenKind = iota // Character data between tags
+	htmlOpen                           // <name attrs>
+	htmlClose                          // </name>
+	htmlSelfClose                      // <name attrs/>
+)
+
+// htmlAttr is a single name="value" attribute of an HTML tag.
+type htmlAttr struct {
+	name  string
+	value string
+}
+
+// htmlToken is a tag or run of text in an HTML-like label.
+// Tag and attribute names are lowercased, since Graphviz treats them case-insensitively.
+type htmlToken struct {
+	kind  htmlTokenKind
+	name  string
+	attrs []htmlAttr
+	text  string
+}
+
+// tokenizeHTML splits the body of an HTML-like label into tags and text.
+// Returns false if the markup is malformed or contains comments.
+func tokenizeHTML(s string) ([]htmlToken, bool) {
+	var tokens []htmlToken
+
+	for pos := 0; pos < len(s); {
+		if s[pos] != '<' {
+			end := strings.IndexByte(s[pos:], '<')
+			if end < 0 {
+				end = len(s) - pos
+			}
+			tokens = append(tokens, htmlToken{kind: htmlText, text: s[pos : pos+end]})
+			pos += end
+			continue
+		}
+
+		if strings.HasPrefix(s[pos:], "<!--") {
+			return nil, false
+		}
+
+		tok, next, ok := scanHTMLTag(s, pos)
+		if !ok {
+			return nil, false
+		}
+		tokens = append(tokens, tok)
+		pos = next
+	}
+
+	return tokens, true
+}
+
+// scanHTMLTag scans the tag starting at s[pos] == '<' and returns it along with the
+// position just past its closing '>'.
+func scanHTMLTag(s string, pos int) (htmlToken, int, bool) {
+	pos++ // skip <
+	tok := htmlToken{kind: htmlOpen}
+	if pos < len(s) && s[pos] == '/' {
+		tok.kind = htmlClose
+		pos++
+	}
+
+	tok.name, pos = scanHTMLName(s, pos)
+	if tok.name == "" {
+		return htmlToken{}, 0, false
+	}
+
+	for {
+		pos = skipHTMLSpace(s, pos)
+		if pos >= len(s) {
+			return htmlToken{}, 0, false
+		}
+
+		switch {
+		case s[pos] == '>':
+			return tok, pos + 1, true
+		case strings.HasPrefix(s[pos:], "/>") && tok.kind == htmlOpen:
+			tok.kind = htmlSelfClose
+			return tok, pos + 2, true
+		case tok.kind == htmlClose:
+			// Closing tags cannot carry attributes
+			return htmlToken{}, 0, false
+		}
+
+		var attr htmlAttr
+		attr.name, pos = scanHTMLName(s, pos)
+		if attr.name == "" {
+			return htmlToken{}, 0, false
+		}
+
+		pos = skipHTMLSpace(s, pos)
+		if pos >= len(s) || s[pos] != '=' {
+			return htmlToken{}, 0, false
+		}
+		pos = skipHTMLSpace(s, pos+1)
+		if pos >= len(s) || (s[pos] != '"' && s[pos] != '\'') {
+			return htmlToken{}, 0, false
+		}
+
+		quote := s[pos]
+		end := strings.IndexByte(s[pos+1:], quote)
+		if end < 0 {
+			return htmlToken{}, 0, false
+		}
+		attr.value = s[pos+1 : pos+1+end]
+		pos += end + 2
+
+		tok.attrs = append(tok.attrs, attr)
+	}
+}
+
+// scanHTMLName scans a tag or attribute name starting at pos, returning it lowercased.
+func scanHTMLName(s string, pos int) (string, int) {
+	start := pos
+	for pos < len(s) && (isIdentChar(s[pos]) || s[pos] == '-') {
+		pos++
+	}
+	return strings.ToLower(s[start:pos]), pos
+}
+
+// skipHTMLSpace returns the position of the first non-whitespace byte at or after pos.
+func skipHTMLSpace(s string, pos int) int {
+	for pos < len(s) && strings.IndexByte(" \t\r\n", s[pos]) >= 0 {
+		pos++
+	}
+	return pos
+}
+
+// htmlParser builds an HTMLLabel from the tokens of an HTML-like label.
+// Every method returns false as soon as it meets markup HTMLLabel cannot represent.
+type htmlParser struct {
+	tokens []htmlToken
+	pos    int
+}
+
+// parseHTMLTable parses the body of an HTML-like label (without the enclosing angle
+// brackets) consisting of a single table. Returns false if the label is not a table
+// or uses tags or attributes that HTMLLabel cannot represent, such as fonts, images,
+// nested tables or rules between rows.
+func parseHTMLTable(s string) (*HTMLLabel, bool) {
+	tokens, ok := tokenizeHTML(s)
+	if !ok {
+		return nil, false
+	}
+
+	p := &htmlParser{tokens: tokens}
+	p.skipSpace()
+	label, ok := p.table()
+	if !ok {
+		return nil, false
+	}
+
+	p.skipSpace()
+	return label, p.pos == len(p.tokens)
+}
+
+// peek returns the current token, or nil if all tokens have been consumed.
+func (p *htmlParser) peek() *htmlToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+// skipSpace consumes whitespace-only text between structural tags.
+func (p *htmlParser) skipSpace() {
+	for tok := p.peek(); tok != nil && tok.kind == htmlText && strings.TrimSpace(tok.text) == ""; tok = p.peek() {
+		p.pos++
+	}
+}
+
+// accept consumes the current token if it is of the given kind and name.
+func (p *htmlParser) accept(kind htmlTokenKind, name string) (*htmlToken, bool) {
+	tok := p.peek()
+	if tok == nil || tok.kind != kind || tok.name != name {
+		return nil, false
+	}
+	p.pos++
+	return tok, true
+}
+
+// table parses <table attrs> rows </table>.
+func (p *htmlParser) table() (*HTMLLabel, bool) {
+	open, ok := p.accept(htmlOpen, "table")
+	if !ok {
+		return nil, false
+	}
+
+	label := HTMLTable()
+	for _, attr := range open.attrs {
+		if !applyTableAttr(label, attr) {
+			return nil, false
+		}
+	}
+
+	for {
+		p.skipSpace()
+		if _, ok := p.accept(htmlClose, "table"); ok {
+			return label, true
+		}
+
+		row, ok := p.row()
+		if !ok {
+			return nil, false
+		}
+		label.rows = append(label.rows, row)
+	}
+}
+
+// applyTableAttr sets a <table> attribute on the label.
+func applyTableAttr(label *HTMLLabel, attr htmlAttr) bool {
+	if attr.name == "bgcolor" {
+		label.BgColor(attr.value)
+		return true
+	}
+
+	n, err := strconv.Atoi(attr.value)
+	if err != nil {
+		return false
+	}
+
+	switch attr.name {
+	case "border":
+		label.Border(n)
+	case "cellborder":
+		label.CellBorder(n)
+	case "cellspacing":
+		label.CellSpacing(n)
+	case "cellpadding":
+		label.CellPadding(n)
+	default:
+		return false
+	}
+	return true
+}
+
+// row parses <tr> cells </tr>.
+func (p *htmlParser) row() (*HTMLRow, bool) {
+	open, ok := p.accept(htmlOpen, "tr")
+	if !ok || len(open.attrs) > 0 {
+		return nil, false
+	}
+
+	row := Row()
+	for {
+		p.skipSpace()
+		if _, ok := p.accept(htmlClose, "tr"); ok {
+			return row, true
+		}
+
+		cell, ok := p.cell()
+		if !ok {
+			return nil, false
+		}
+		row.cells = append(row.cells, cell)
+	}
+}
+
+// cell parses <td attrs> contents </td>.
+func (p *htmlParser) cell() (*HTMLCell, bool) {
+	open, ok := p.accept(htmlOpen, "td")
+	if !ok {
+		return nil, false
+	}
+
+	cell := Cell()
+	for _, attr := range open.attrs {
+		if !applyCellAttr(cell, attr) {
+			return nil, false
+		}
+	}
+
+	contents, ok := p.contents("td", TextContent{})
+	if !ok {
+		return nil, false
+	}
+	cell.contents = contents
+
+	return cell, true
+}
+
+// applyCellAttr sets a <td> attribute on the cell.
+func applyCellAttr(cell *HTMLCell, attr htmlAttr) bool {
+	switch attr.name {
+	case "port":
+		cell.Port(attr.value)
+	case "bgcolor":
+		cell.BgColor(attr.value)
+	case "align":
+		align := Alignment(strings.ToLower(attr.value))
+		switch align {
+		case AlignLeft, AlignRight, AlignCenter, AlignText:
+			cell.Align(align)
+		default:
+			return false
+		}
+	case "colspan", "rowspan":
+		n, err := strconv.Atoi(attr.value)
+		if err != nil {
+			return false
+		}
+		if attr.name == "colspan" {
+			cell.ColSpan(n)
+		} else {
+			cell.RowSpan(n)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// contents parses cell contents up to and including the closing tag named end.
+// Text picks up the formatting of every enclosing <b>, <i>, <u>, <sub> and <sup> tag,
+// which is accumulated in format.
+func (p *htmlParser) contents(end string, format TextContent) ([]Content, bool) {
+	var contents []Content
+
+	for {
+		tok := p.peek()
+		if tok == nil {
+			return nil, false
+		}
+		p.pos++
+
+		switch tok.kind {
+		case htmlText:
+			text := format
+			text.text = tok.text
+			contents = append(contents, &text)
+		case htmlClose:
+			return contents, tok.name == end
+		case htmlSelfClose:
+			if len(tok.attrs) > 0 {
+				return nil, false
+			}
+			switch tok.name {
+			case "br":
+				contents = append(contents, BR())
+			case "hr":
+				contents = append(contents, HR())
+			default:
+				return nil, false
+			}
+		case htmlOpen:
+			nested, ok := withFormat(format, tok)
+			if !ok {
+				return nil, false
+			}
+			inner, ok := p.contents(tok.name, nested)
+			if !ok {
+				return nil, false
+			}
+			contents = append(contents, inner...)
+		}
+	}
+}
+
+// withFormat returns format with the formatting of the opening tag tok added.
+// Returns false for tags that are not text formatting or that carry attributes.
+func withFormat(format TextContent, tok *htmlToken) (TextContent, bool) {
+	if len(tok.attrs) > 0 {
+		return format, false
+	}
+
+	switch tok.name {
+	case "b":
+		format.bold = true
+	case "i":
+		format.italic = true
+	case "u":
+		format.underline = true
+	case "sub":
+		format.subscript = true
+	case "sup":
+		format.superscript = true
+	default:
+		return format, false
+	}
+
+	// TextContent renders only one of subscript and superscript
+	return format, !(format.subscript && format.superscript)
+}
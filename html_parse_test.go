@@ -0,0 +1,119 @@
+// ABOUTME: Tests for parsing HTML-like label markup into HTMLLabel trees.
+// ABOUTME: Verifies tables, cell attributes, text formatting and unsupported constructs.
+package goraffe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHTMLTable(t *testing.T) {
+	t.Run("parses table structure and attributes", func(t *testing.T) {
+		asrt := assert.New(t)
+		req := require.New(t)
+
+		label, ok := parseHTMLTable(`
+			<TABLE BORDER="0" cellborder='1' cellspacing="2" cellpadding="4" bgcolor="white">
+				<TR><TD PORT="in" colspan="2" align="LEFT">Input</TD></TR>
+				<tr>
+					<td port="a" bgcolor="lightblue">A</td>
+					<td rowspan="3" align="right">B</td>
+				</tr>
+			</TABLE>
+		`)
+		req.True(ok, "expected table to be supported")
+
+		rows := label.Rows()
+		req.Len(rows, 2)
+		req.Len(rows[0].Cells(), 1)
+		req.Len(rows[1].Cells(), 2)
+
+		in := rows[0].Cells()[0]
+		asrt.Equal("in", in.GetPort().ID())
+		asrt.Equal(2, in.colSpan)
+		asrt.Equal(AlignLeft, in.align)
+		asrt.Equal(3, rows[1].Cells()[1].rowSpan)
+		asrt.Same(rows[1].Cells()[0].GetPort(), label.GetPort("a"))
+
+		asrt.Equal(
+			`<<table border="0" cellborder="1" cellspacing="2" cellpadding="4" bgcolor="white">`+
+				`<tr><td port="in" colspan="2" align="left">Input</td></tr>`+
+				`<tr><td port="a" bgcolor="lightblue">A</td><td rowspan="3" align="right">B</td></tr>`+
+				`</table>>`,
+			label.String(),
+		)
+	})
+
+	t.Run("parses formatted text, line breaks and rules", func(t *testing.T) {
+		asrt := assert.New(t)
+		req := require.New(t)
+
+		label, ok := parseHTMLTable(`<table><tr><td><b>bold <i>both</i></b><br/>x<SUB>2</SUB><hr/></td></tr></table>`)
+		req.True(ok)
+
+		contents := label.Rows()[0].Cells()[0].Contents()
+		req.Len(contents, 6)
+
+		bold := contents[0].(*TextContent)
+		asrt.Equal("bold ", bold.Text())
+		asrt.True(bold.bold)
+		asrt.False(bold.italic)
+
+		both := contents[1].(*TextContent)
+		asrt.Equal("both", both.Text())
+		asrt.True(both.bold)
+		asrt.True(both.italic)
+
+		asrt.IsType(&LineBreak{}, contents[2])
+		asrt.False(contents[3].(*TextContent).subscript)
+		asrt.True(contents[4].(*TextContent).subscript)
+		asrt.IsType(&HorizontalRule{}, contents[5])
+	})
+
+	t.Run("round trips generated labels", func(t *testing.T) {
+		asrt := assert.New(t)
+		req := require.New(t)
+
+		original := HTMLTable(
+			Row(Cell(Text("Name").Bold().Underline()).ColSpan(2)),
+			Row(Cell(Text("x"), BR(), Text("y").Sup()).Port("p1"), Cell(Text("z")).BgColor("gray").Align(AlignText)),
+		).Border(1).CellPadding(3)
+
+		html := original.String()
+		label, ok := parseHTMLTable(html[1 : len(html)-1])
+		req.True(ok)
+
+		asrt.Equal(html, label.String())
+	})
+
+	t.Run("rejects unsupported markup", func(t *testing.T) {
+		tests := []struct {
+			name string
+			html string
+		}{
+			{"plain text", `<b>bold</b>`},
+			{"font tag", `<table><tr><td><font color="red">x</font></td></tr></table>`},
+			{"image", `<table><tr><td><img src="a.png"/></td></tr></table>`},
+			{"nested table", `<table><tr><td><table><tr><td>x</td></tr></table></td></tr></table>`},
+			{"rule between rows", `<table><tr><td>a</td></tr><hr/><tr><td>b</td></tr></table>`},
+			{"unknown table attribute", `<table style="rounded"><tr><td>x</td></tr></table>`},
+			{"unknown cell attribute", `<table><tr><td width="10">x</td></tr></table>`},
+			{"non-numeric border", `<table border="thick"><tr><td>x</td></tr></table>`},
+			{"invalid alignment", `<table><tr><td align="middle">x</td></tr></table>`},
+			{"mismatched close", `<table><tr><td><b>x</i></td></tr></table>`},
+			{"unterminated", `<table><tr><td>x</td></tr>`},
+			{"trailing content", `<table></table>tail`},
+			{"comment", `<table><!-- note --><tr><td>x</td></tr></table>`},
+			{"subscript and superscript", `<table><tr><td><sub><sup>x</sup></sub></td></tr></table>`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, ok := parseHTMLTable(tt.html)
+				assert.False(t, ok, "expected markup to be unsupported")
+			})
+		}
+	})
+}
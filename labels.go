@@ -62,6 +62,13 @@ func (c *HTMLCell) GetPort() *Port {
 	return c.portRef
 }
 
+// Contents returns a copy of the cell's contents in order.
+func (c *HTMLCell) Contents() []Content {
+	ret := make([]Content, len(c.contents))
+	copy(ret, c.contents)
+	return ret
+}
+
 func Row(cells ...*HTMLCell) *HTMLRow {
 	return &HTMLRow{
 		cells: cells,
@@ -89,6 +96,19 @@ func HTMLTable(rows ...*HTMLRow) *HTMLLabel {
 	}
 }
 
+// Rows returns a copy of the table's rows in order.
+func (l *HTMLLabel) Rows() []*HTMLRow {
+	ret := make([]*HTMLRow, len(l.rows))
+	copy(ret, l.rows)
+	return ret
+}
+
+// GetPort returns the port with the given ID defined by one of the table's cells,
+// or nil if no cell declares it.
+func (l *HTMLLabel) GetPort(id string) *Port {
+	return l.findPort(id)
+}
+
 func (l *HTMLLabel) Border(n int) *HTMLLabel {
 	l.border = &n
 	return l
@@ -126,6 +146,52 @@ func (l *HTMLLabel) setNodeContext(nodeID string) {
 	}
 }
 
+// findPort returns the port with the given ID defined in this label, or nil if none exists.
+func (l *HTMLLabel) findPort(id string) *Port {
+	for _, row := range l.rows {
+		for _, cell := range row.cells {
+			if cell.portRef != nil && cell.portRef.id == id {
+				return cell.portRef
+			}
+		}
+	}
+
+	return nil
+}
+
+// clone returns a deep copy of the label with its own rows, cells, contents and ports.
+func (l *HTMLLabel) clone() *HTMLLabel {
+	copied := *l
+	copied.rows = make([]*HTMLRow, len(l.rows))
+
+	for i, row := range l.rows {
+		cells := make([]*HTMLCell, len(row.cells))
+		for j, cell := range row.cells {
+			cells[j] = cell.clone()
+		}
+		copied.rows[i] = &HTMLRow{cells: cells}
+	}
+
+	return &copied
+}
+
+// clone returns a deep copy of the cell with its own contents and port.
+func (c *HTMLCell) clone() *HTMLCell {
+	copied := *c
+	copied.contents = make([]Content, len(c.contents))
+
+	for i, content := range c.contents {
+		copied.contents[i] = content.cloneContent()
+	}
+
+	if c.portRef != nil {
+		port := *c.portRef
+		copied.portRef = &port
+	}
+
+	return &copied
+}
+
 func (l *HTMLLabel) String() string {
 	result := "<"
 
@@ -66,6 +66,8 @@ func TestLexer_QuotedStrings(t *testing.T) {
 		{`"with \\backslash"`, `with \backslash`},
 		{`"with\nnewline"`, "with\nnewline"},
 		{`"with\ttab"`, "with\ttab"},
+		{`"record \| escape"`, `record \| escape`},
+		{`"left\lright\r"`, `left\lright\r`},
 		{`""`, ""},
 	}
 
@@ -166,6 +168,87 @@ func TestLexer_Comments(t *testing.T) {
 	}
 }
 
+func TestLexer_LeadingComments(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected [][]string
+	}{
+		{
+			name:     "line comments lead the next token",
+			input:    "// first\n// second\nA",
+			expected: [][]string{{"first", "second"}},
+		},
+		{
+			name:     "block comments have their decoration removed",
+			input:    "/*\n * one\n * two\n */\nA /* inline */ B",
+			expected: [][]string{{"one\ntwo"}, nil},
+		},
+		{
+			name:     "trailing comments are not attached to the next token",
+			input:    "A; // about A\nB",
+			expected: [][]string{nil, nil, nil},
+		},
+		{
+			name:     "comments on their own line after a token lead the next",
+			input:    "A;\n// about B\nB",
+			expected: [][]string{nil, nil, {"about B"}},
+		},
+		{
+			name:     "preprocessor lines are skipped",
+			input:    "#line 1 \"g.dot\"\nA # B",
+			expected: [][]string{nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asrt := assert.New(t)
+
+			lexer := NewLexer(tt.input)
+			for i, expected := range tt.expected {
+				tok := lexer.Next()
+				asrt.Equal(expected, lexer.Comments(), "Token %d (%s) comments", i, tok)
+			}
+		})
+	}
+
+	t.Run("peeking keeps the current token's comments", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		lexer := NewLexer("// about A\nA\n// about B\nB")
+		lexer.Next()
+		peeked := lexer.Peek()
+
+		asrt.Equal([]string{"about A"}, lexer.Comments())
+		asrt.Equal(peeked, lexer.Next())
+		asrt.Equal([]string{"about B"}, lexer.Comments())
+	})
+}
+
+func TestLexer_EmittedComments(t *testing.T) {
+	t.Run("returns comments and preprocessor lines as tokens", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		lexer := NewLexer("# 1\nA // a\n/* b */ B")
+		lexer.emitting = true
+
+		expected := []Token{
+			{Type: TokenComment, Value: "# 1"},
+			{Type: TokenIdent, Value: "A"},
+			{Type: TokenComment, Value: "// a"},
+			{Type: TokenComment, Value: "/* b */"},
+			{Type: TokenIdent, Value: "B"},
+			{Type: TokenEOF},
+		}
+		for i, exp := range expected {
+			tok := lexer.Next()
+			asrt.Equal(exp.Type, tok.Type, "Token %d type", i)
+			asrt.Equal(exp.Value, tok.Value, "Token %d value", i)
+		}
+	})
+}
+
 func TestLexer_CompleteGraph(t *testing.T) {
 	asrt := assert.New(t)
 
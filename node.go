@@ -1,15 +1,11 @@
 package goraffe
 
-import (
-	"sort"
-	"strings"
-)
-
 // Node represents a node (vertex) in a graph.
 // Each node has a unique ID and optional visual attributes like shape, color, and label.
 type Node struct {
-	id    string
-	attrs *NodeAttributes
+	id      string
+	attrs   *NodeAttributes
+	comment string
 }
 
 // NewNode creates a new node with the given ID and optional attributes.
@@ -55,19 +51,5 @@ func (n *Node) Attrs() *NodeAttributes {
 // String returns the DOT representation of the node with its attributes.
 // The output includes the node ID and any set attributes in DOT format.
 func (n *Node) String() string {
-	builder := strings.Builder{}
-	builder.WriteString(quoteDOTID(n.ID()))
-
-	attrs := n.attrs.List()
-	var attrsStr string
-
-	if len(attrs) > 0 {
-		sort.Strings(attrs)
-		attrsStr = "[" + strings.Join(attrs, ", ") + "]"
-
-		builder.WriteString(" ")
-		builder.WriteString(attrsStr)
-	}
-
-	return builder.String()
+	return defaultPrinter.node(n, 0)
 }
@@ -1,7 +1,6 @@
 package goraffe
 
 import (
-	"fmt"
 	"maps"
 )
 
@@ -17,6 +16,7 @@ const (
 	ShapeEllipse   Shape = "ellipse"   // Elliptical shape (default)
 	ShapeDiamond   Shape = "diamond"   // Diamond shape
 	ShapeRecord    Shape = "record"    // Record-based shape for structured nodes
+	ShapeMrecord   Shape = "Mrecord"   // Record-based shape with rounded corners
 	ShapePlaintext Shape = "plaintext" // Text with no surrounding shape
 )
 
@@ -56,6 +56,49 @@ func (a *NodeAttributes) setCustom(key, value string) {
 	a.custom[key] = value
 }
 
+// clone returns a copy of the attributes with its own custom attribute map
+// and its own copies of any HTML or record label.
+func (a *NodeAttributes) clone() *NodeAttributes {
+	copied := *a
+	copied.custom = maps.Clone(a.custom)
+
+	if a.htmlLabel != nil {
+		copied.htmlLabel = a.htmlLabel.clone()
+	}
+
+	if a.recordLabel != nil {
+		copied.recordLabel = a.recordLabel.clone()
+	}
+
+	return &copied
+}
+
+// merge copies every attribute set in src, including custom attributes, onto a.
+// Values set in src take precedence.
+func (a *NodeAttributes) merge(src *NodeAttributes) {
+	src.applyNode(a)
+
+	for k, v := range src.custom {
+		a.setCustom(k, v)
+	}
+}
+
+// port returns the port with the given ID from the node's HTML or record label,
+// or nil if neither label defines it.
+func (a *NodeAttributes) port(id string) *Port {
+	if a.htmlLabel != nil {
+		if p := a.htmlLabel.findPort(id); p != nil {
+			return p
+		}
+	}
+
+	if a.recordLabel != nil {
+		return a.recordLabel.findPort(id)
+	}
+
+	return nil
+}
+
 // Label returns the node label. Returns empty string if unset.
 // Note: An empty string return value may indicate either an unset label or a label
 // explicitly set to empty string.
@@ -184,49 +227,53 @@ func (a NodeAttributes) applyNode(dst *NodeAttributes) {
 	}
 }
 
+// List returns a slice of DOT attribute strings for rendering.
+// Only attributes that have been explicitly set are included.
 func (a NodeAttributes) List() []string {
-	attrs := make([]string, 0)
+	return formatAttributes(a.attributes())
+}
+
+// attributes returns the attributes that have been explicitly set, typed attributes
+// first in declaration order followed by custom attributes sorted by name.
+func (a NodeAttributes) attributes() []attribute {
+	attrs := make([]attribute, 0)
 
 	// Label precedence: raw HTML > HTML > record > regular label
 	switch {
 	case a.rawHTMLLabel != nil:
-		attrs = append(attrs, fmt.Sprintf(`label=%s`, a.RawHTMLLabel()))
+		attrs = append(attrs, attribute{name: "label", value: a.RawHTMLLabel(), html: true})
 	case a.htmlLabel != nil:
-		attrs = append(attrs, fmt.Sprintf(`label=%s`, a.htmlLabel.String()))
+		attrs = append(attrs, attribute{name: "label", value: a.htmlLabel.String(), html: true})
 	case a.recordLabel != nil:
-		attrs = append(attrs, fmt.Sprintf(`label="%s"`, escapeDOTString(a.recordLabel.String())))
+		attrs = append(attrs, attribute{name: "label", value: a.recordLabel.String()})
 	case a.label != nil:
-		attrs = append(attrs, fmt.Sprintf(`label="%s"`, escapeDOTString(a.Label())))
+		attrs = append(attrs, attribute{name: "label", value: a.Label()})
 	}
 
 	if a.shape != nil {
-		attrs = append(attrs, fmt.Sprintf(`shape="%s"`, escapeDOTString(string(a.Shape()))))
+		attrs = append(attrs, attribute{name: "shape", value: string(a.Shape())})
 	}
 
 	if a.color != nil {
-		attrs = append(attrs, fmt.Sprintf(`color="%s"`, escapeDOTString(a.Color())))
+		attrs = append(attrs, attribute{name: "color", value: a.Color()})
 	}
 
 	if a.fillColor != nil {
-		attrs = append(attrs, fmt.Sprintf(`fillcolor="%s"`, escapeDOTString(a.FillColor())))
+		attrs = append(attrs, attribute{name: "fillcolor", value: a.FillColor()})
 		// HACK: this is a temporary hack to ensure a set fillcolor appears as expected
 		// When we support the `style` attribute for nodes, we'll allow this to be set
 		// when the fillcolor is defined, but overridden later. For now, this.
 		// -- MRB, 2026-01-03
-		attrs = append(attrs, `style="filled"`)
+		attrs = append(attrs, attribute{name: "style", value: "filled"})
 	}
 
 	if a.fontName != nil {
-		attrs = append(attrs, fmt.Sprintf(`fontname="%s"`, escapeDOTString(a.FontName())))
+		attrs = append(attrs, attribute{name: "fontname", value: a.FontName()})
 	}
 
 	if a.fontSize != nil {
-		attrs = append(attrs, fmt.Sprintf(`fontsize="%g"`, a.FontSize()))
-	}
-
-	for k, v := range a.custom {
-		attrs = append(attrs, fmt.Sprintf(`%s="%s"`, k, escapeDOTString(v)))
+		attrs = append(attrs, attribute{name: "fontsize", value: formatFloat(a.FontSize())})
 	}
 
-	return attrs
+	return appendCustom(attrs, a.custom)
 }
@@ -5,8 +5,10 @@ package goraffe
 import (
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"unicode"
 )
@@ -47,6 +49,9 @@ const (
 	TokenArrow
 	// TokenHTML represents HTML strings <...>
 	TokenHTML
+	// TokenComment represents a comment or # preprocessor line, with its delimiters.
+	// Comment tokens are only produced for FormatDOT; the parser reads comments from Lexer.Comments.
+	TokenComment
 )
 
 // String returns a string representation of the token type.
@@ -84,6 +89,8 @@ func (t TokenType) String() string {
 		return "ARROW"
 	case TokenHTML:
 		return "HTML"
+	case TokenComment:
+		return "COMMENT"
 	default:
 		return "UNKNOWN"
 	}
@@ -107,11 +114,17 @@ func (t Token) String() string {
 
 // Lexer tokenizes DOT language input.
 type Lexer struct {
-	input  string
-	pos    int
-	line   int
-	col    int
-	peeked *Token
+	input         string
+	pos           int
+	line          int
+	col           int
+	peeked        *Token
+	comments      []string // Leading comments collected for the next token
+	leading       []string // Leading comments of the token most recently returned by Next
+	peekedLeading []string // Leading comments of the peeked token
+	lastLine      int      // Line on which the previous token ended, 0 before the first token
+	tokStart      int      // Position at which the most recently scanned token starts
+	emitting      bool     // Return comments as TokenComment tokens instead of collecting them
 }
 
 // NewLexer creates a new lexer for the given input string.
@@ -129,11 +142,29 @@ func (l *Lexer) Next() Token {
 	if l.peeked != nil {
 		tok := *l.peeked
 		l.peeked = nil
+		l.leading, l.peekedLeading = l.peekedLeading, nil
 		return tok
 	}
 
 	l.skipWhitespaceAndComments()
 
+	l.tokStart = l.pos
+	tok := l.scanToken()
+	l.leading, l.comments = l.comments, nil
+	l.lastLine = l.line
+
+	return tok
+}
+
+// Comments returns the text of the comments on the lines before the token most recently
+// returned by Next, without their delimiters. Comments that follow another token on the
+// same line are not included.
+func (l *Lexer) Comments() []string {
+	return l.leading
+}
+
+// scanToken scans the token starting at the current position.
+func (l *Lexer) scanToken() Token {
 	if l.pos >= len(l.input) {
 		return Token{Type: TokenEOF, Line: l.line, Col: l.col}
 	}
@@ -142,6 +173,11 @@ func (l *Lexer) Next() Token {
 	startCol := l.col
 	ch := l.input[l.pos]
 
+	// Comments are only left for scanning when they are emitted as tokens
+	if l.skipComment(ch) {
+		return Token{Type: TokenComment, Value: l.input[l.tokStart:l.pos], Line: startLine, Col: startCol}
+	}
+
 	// Try to scan single character token
 	if tok, ok := l.scanSingleCharToken(ch, startLine, startCol); ok {
 		return tok
@@ -241,8 +277,9 @@ func (l *Lexer) scanArrowToken(ch byte, line, col int) (Token, bool) {
 // Peek returns the next token without consuming it.
 func (l *Lexer) Peek() Token {
 	if l.peeked == nil {
+		current := l.leading
 		tok := l.Next()
-		l.peeked = &tok
+		l.peeked, l.peekedLeading, l.leading = &tok, l.leading, current
 	}
 	return *l.peeked
 }
@@ -260,7 +297,9 @@ func (l *Lexer) advance() {
 	}
 }
 
-// skipWhitespaceAndComments skips whitespace and comments.
+// skipWhitespaceAndComments skips whitespace and comments, collecting the text of
+// comments that start on a line of their own as leading comments of the next token.
+// # preprocessor lines are skipped without being collected.
 func (l *Lexer) skipWhitespaceAndComments() {
 	for l.pos < len(l.input) {
 		ch := l.input[l.pos]
@@ -271,36 +310,63 @@ func (l *Lexer) skipWhitespaceAndComments() {
 			continue
 		}
 
-		// Skip // comments
-		if ch == '/' && l.pos+1 < len(l.input) && l.input[l.pos+1] == '/' {
-			l.advance() // skip first /
-			l.advance() // skip second /
-			for l.pos < len(l.input) && l.input[l.pos] != '\n' {
-				l.advance()
-			}
-			continue
+		if l.emitting {
+			break
 		}
 
-		// Skip /* */ comments
-		if ch == '/' && l.pos+1 < len(l.input) && l.input[l.pos+1] == '*' {
-			l.advance() // skip /
-			l.advance() // skip *
-			for l.pos+1 < len(l.input) {
-				if l.input[l.pos] == '*' && l.input[l.pos+1] == '/' {
-					l.advance() // skip *
-					l.advance() // skip /
-					break
-				}
-				l.advance()
-			}
-			continue
+		start, startLine := l.pos, l.line
+		if !l.skipComment(ch) {
+			// Not whitespace or comment
+			break
 		}
 
-		// Not whitespace or comment
-		break
+		// Comments after a token on the same line trail that token rather than lead the next
+		if ch != '#' && startLine != l.lastLine {
+			l.comments = append(l.comments, commentText(l.input[start:l.pos]))
+		}
 	}
 }
 
+// skipComment skips the // or /* */ comment or # preprocessor line starting at the
+// current position. Returns false if there is no comment at the current position.
+func (l *Lexer) skipComment(ch byte) bool {
+	// Lines starting with # are C preprocessor output, which DOT ignores
+	if ch == '#' && (l.pos == 0 || l.input[l.pos-1] == '\n') {
+		for l.pos < len(l.input) && l.input[l.pos] != '\n' {
+			l.advance()
+		}
+		return true
+	}
+
+	if ch != '/' || l.pos+1 >= len(l.input) {
+		return false
+	}
+
+	switch l.input[l.pos+1] {
+	case '/':
+		l.advance() // skip first /
+		l.advance() // skip second /
+		for l.pos < len(l.input) && l.input[l.pos] != '\n' {
+			l.advance()
+		}
+	case '*':
+		l.advance() // skip /
+		l.advance() // skip *
+		for l.pos+1 < len(l.input) {
+			if l.input[l.pos] == '*' && l.input[l.pos+1] == '/' {
+				l.advance() // skip *
+				l.advance() // skip /
+				break
+			}
+			l.advance()
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
 // scanString scans a quoted string token.
 func (l *Lexer) scanString(startLine, startCol int) Token {
 	l.advance() // skip opening quote
@@ -320,13 +386,14 @@ func (l *Lexer) scanString(startLine, startCol int) Token {
 				sb.WriteByte('\n')
 			case 't':
 				sb.WriteByte('\t')
-			case 'r':
-				sb.WriteByte('\r')
 			case '\\':
 				sb.WriteByte('\\')
 			case '"':
 				sb.WriteByte('"')
 			default:
+				// Keep other escapes, such as the \l and \r line breaks or record
+				// label \| and \{, for later stages
+				sb.WriteByte('\\')
 				sb.WriteByte(next)
 			}
 			l.advance()
@@ -411,6 +478,18 @@ func isIdentChar(ch byte) bool {
 type Parser struct {
 	lexer   *Lexer
 	current Token
+	scopes  []defaultsScope // Defaults of the graph and enclosing subgraphs, innermost last
+	comment string          // Leading comment of the current statement, until claimed
+}
+
+// defaultsScope holds the attributes set by node [...] and edge [...] statements that are
+// applied directly to the nodes first mentioned and the edges created after them in a
+// block, including those inherited from enclosing blocks. Statements made before a block
+// holds any node or edge become the graph's or subgraph's defaults instead; later ones
+// are scoped, as recorded defaults would also reach the nodes and edges declared before them.
+type defaultsScope struct {
+	node map[string]attrValue
+	edge map[string]attrValue
 }
 
 // newParser creates a new parser for the given input string.
@@ -442,6 +521,35 @@ func (p *Parser) match(tokenType TokenType) bool {
 	return p.current.Type == tokenType
 }
 
+// leadingComment returns the comments before the current token, joined into one comment.
+func (p *Parser) leadingComment() string {
+	return strings.Join(p.lexer.Comments(), "\n")
+}
+
+// claimComment moves the leading comment of the current statement, if any, into dst.
+// Only the first element a statement declares receives its comment.
+func (p *Parser) claimComment(dst *string) {
+	if p.comment != "" {
+		*dst = p.comment
+		p.comment = ""
+	}
+}
+
+// appendComment adds the leading comment of the current statement, if any, to the end
+// of dst. Attribute and default statements declare no element of their own, so their
+// comments are kept with the graph or subgraph they apply to.
+func (p *Parser) appendComment(dst *string) {
+	if p.comment == "" {
+		return
+	}
+
+	if *dst != "" {
+		*dst += "\n"
+	}
+	*dst += p.comment
+	p.comment = ""
+}
+
 // matchKeyword returns true if the current token is an identifier with the given value.
 func (p *Parser) matchKeyword(keyword string) bool {
 	return p.current.Type == TokenIdent && p.current.Value == keyword
@@ -450,7 +558,8 @@ func (p *Parser) matchKeyword(keyword string) bool {
 // parseGraph parses a complete DOT graph.
 // Syntax: [strict] (graph|digraph) [ID] { stmt_list }
 func (p *Parser) parseGraph() (*Graph, error) {
-	// Check for 'strict' keyword
+	// Check for 'strict' keyword, keeping the comments before it for the graph
+	comment := p.leadingComment()
 	strict := false
 	if p.matchKeyword("strict") {
 		strict = true
@@ -490,9 +599,13 @@ func (p *Parser) parseGraph() (*Graph, error) {
 
 	g := NewGraph(opts...)
 	g.name = name
+	g.comment = comment
 
 	// Parse statements
-	if err := p.parseStmtList(g); err != nil {
+	p.pushScope()
+	err := p.parseStmtList(g)
+	p.popScope()
+	if err != nil {
 		return nil, err
 	}
 
@@ -501,15 +614,86 @@ func (p *Parser) parseGraph() (*Graph, error) {
 		return nil, err
 	}
 
+	// Labels may be declared after the edges that use their ports
+	resolveRecordLabels(g)
+	resolveEdgePorts(g)
+
 	return g, nil
 }
 
+// resolveRecordLabels parses the labels of record-shaped nodes into RecordLabels.
+// A node's shape may come from the default node attributes, so this waits until the
+// whole graph has been parsed. Labels that are not valid record syntax are left as-is.
+func resolveRecordLabels(g *Graph) {
+	subgraphShapes := subgraphDefaultShapes(g)
+	for _, n := range g.nodeOrder {
+		shape, inSubgraph := subgraphShapes[n.id]
+		if !inSubgraph {
+			shape = g.defaultNodeAttrs.Shape()
+		}
+		if n.attrs.shape != nil {
+			shape = *n.attrs.shape
+		}
+		if n.attrs.label == nil || (shape != ShapeRecord && shape != ShapeMrecord) {
+			continue
+		}
+
+		if label, ok := parseRecordLabel(*n.attrs.label); ok {
+			label.setNodeContext(n.id)
+			n.attrs.recordLabel = label
+			n.attrs.label = nil
+		}
+	}
+}
+
+// subgraphDefaultShapes maps each node written in a subgraph block to the default shape
+// in effect where it is first written, following the order DOTPrinter writes blocks in.
+func subgraphDefaultShapes(g *Graph) map[string]Shape {
+	shapes := make(map[string]Shape)
+
+	var visit func(sg *Subgraph, inherited Shape)
+	visit = func(sg *Subgraph, inherited Shape) {
+		if sg.defaultNodeAttrs != nil && sg.defaultNodeAttrs.shape != nil {
+			inherited = *sg.defaultNodeAttrs.shape
+		}
+		for _, nested := range sg.subgraphs {
+			visit(nested, inherited)
+		}
+		for _, n := range sg.nodeOrder {
+			if _, seen := shapes[n.id]; !seen {
+				shapes[n.id] = inherited
+			}
+		}
+	}
+
+	for _, sg := range g.subgraphs {
+		visit(sg, g.defaultNodeAttrs.Shape())
+	}
+
+	return shapes
+}
+
+// resolveEdgePorts points the ports parsed from edge endpoints at the matching ports
+// declared in the endpoint nodes' labels, so edges share Port values with those labels.
+func resolveEdgePorts(g *Graph) {
+	for e := range g.edges.all() {
+		if from := g.GetNode(e.from.ID()); from != nil {
+			e.attrs.fromPort = rebindPort(e.attrs.fromPort, from)
+		}
+		if to := g.GetNode(e.to.ID()); to != nil {
+			e.attrs.toPort = rebindPort(e.attrs.toPort, to)
+		}
+	}
+}
+
 // parseStmtList parses a list of statements until a closing brace.
 func (p *Parser) parseStmtList(g *Graph) error {
 	for !p.match(TokenRBrace) && !p.match(TokenEOF) {
+		p.comment = p.leadingComment()
 		if err := p.parseStmt(g); err != nil {
 			return err
 		}
+		p.comment = ""
 
 		// Skip optional semicolon
 		if p.match(TokenSemi) {
@@ -547,11 +731,11 @@ func (p *Parser) parseStmt(g *Graph) error {
 		// Check if this subgraph is followed by an arrow (edge statement)
 		if p.match(TokenArrow) {
 			// Subgraph is used as edge endpoint
-			nodeIDs := make([]string, 0)
+			endpoints := make([]endpoint, 0)
 			for _, node := range sg.Nodes() {
-				nodeIDs = append(nodeIDs, node.ID())
+				endpoints = append(endpoints, endpoint{id: node.ID()})
 			}
-			return p.parseEdgeStmtWithNodes(g, nodeIDs)
+			return p.parseEdgeStmtWithNodes(g, endpoints)
 		}
 
 		// Just a standalone subgraph declaration
@@ -575,10 +759,17 @@ func (p *Parser) parseID() (string, error) {
 	}
 }
 
+// attrValue is a parsed attribute value, remembering whether it was written as an
+// HTML string (<...>) rather than an identifier, number or quoted string.
+type attrValue struct {
+	value string
+	html  bool
+}
+
 // parseAttrList parses an attribute list [attr=value, attr=value, ...].
 // Returns a map of attribute key-value pairs.
-func (p *Parser) parseAttrList() (map[string]string, error) {
-	attrs := make(map[string]string)
+func (p *Parser) parseAttrList() (map[string]attrValue, error) {
+	attrs := make(map[string]attrValue)
 
 	if err := p.expect(TokenLBracket); err != nil {
 		return nil, err
@@ -598,12 +789,13 @@ func (p *Parser) parseAttrList() (map[string]string, error) {
 		}
 
 		// Parse attribute value
+		html := p.match(TokenHTML)
 		value, err := p.parseID()
 		if err != nil {
 			return nil, err
 		}
 
-		attrs[name] = value
+		attrs[name] = attrValue{value: value, html: html}
 
 		// Skip optional comma or semicolon
 		if p.match(TokenComma) || p.match(TokenSemi) {
@@ -624,7 +816,7 @@ func (p *Parser) parseNodeOrEdgeStmt(g *Graph) error {
 	// Check if this starts with a subgraph (for edge endpoints)
 	if p.matchKeyword("subgraph") || p.match(TokenLBrace) {
 		// This must be an edge with subgraph as endpoint
-		nodeIDs, err := p.parseEdgeEndpoint(g)
+		nodes, err := p.parseEdgeEndpoint(g)
 		if err != nil {
 			return err
 		}
@@ -634,28 +826,95 @@ func (p *Parser) parseNodeOrEdgeStmt(g *Graph) error {
 			return fmt.Errorf("subgraph must be followed by edge operator at %d:%d", p.current.Line, p.current.Col)
 		}
 
-		return p.parseEdgeStmtWithNodes(g, nodeIDs)
+		return p.parseEdgeStmtWithNodes(g, nodes)
 	}
 
-	// Parse first ID
-	id, err := p.parseID()
+	// Parse first node reference
+	first, err := p.parseNodeRef()
 	if err != nil {
 		return err
 	}
 
+	// Check if this is a graph attribute statement (ID '=' ID)
+	if p.match(TokenEqual) && first.port == nil {
+		attrs, err := p.parseAttrStmt(first.id)
+		if err != nil {
+			return err
+		}
+		return p.applyDefaultAttrs(g, "graph", attrs)
+	}
+
 	// Check if this is an edge statement (next token is arrow)
 	if p.match(TokenArrow) {
-		return p.parseEdgeStmtWithNodes(g, []string{id})
+		return p.parseEdgeStmtWithNodes(g, []endpoint{first})
+	}
+
+	// Otherwise, it's a node statement; like Graphviz, any port is ignored
+	return p.parseNodeStmt(g, first.id)
+}
+
+// endpoint is a node referenced in an edge statement, with the port it connects to, if any.
+type endpoint struct {
+	id   string
+	port *Port
+}
+
+// parseNodeRef parses a node reference: ID [':' port [':' compass_pt]] or ID ':' compass_pt.
+// A lone name after the colon is treated as a compass point if it names one, and as a
+// port otherwise. The returned port, if any, is wired to the referenced node.
+func (p *Parser) parseNodeRef() (endpoint, error) {
+	id, err := p.parseID()
+	if err != nil {
+		return endpoint{}, err
+	}
+
+	if !p.match(TokenColon) {
+		return endpoint{id: id}, nil
+	}
+	p.advance() // consume colon
+
+	name, err := p.parseID()
+	if err != nil {
+		return endpoint{}, err
 	}
 
-	// Otherwise, it's a node statement
-	return p.parseNodeStmt(g, id)
+	if !p.match(TokenColon) {
+		if isCompassPoint(name) {
+			return endpoint{id: id, port: &Port{nodeID: id, compass: CompassPoint(name)}}, nil
+		}
+		return endpoint{id: id, port: &Port{id: name, nodeID: id}}, nil
+	}
+	p.advance() // consume colon
+
+	line, col := p.current.Line, p.current.Col
+	compass, err := p.parseID()
+	if err != nil {
+		return endpoint{}, err
+	}
+	if !isCompassPoint(compass) {
+		return endpoint{}, fmt.Errorf("invalid compass point %q at %d:%d", compass, line, col)
+	}
+
+	return endpoint{id: id, port: &Port{id: name, nodeID: id, compass: CompassPoint(compass)}}, nil
+}
+
+// edgeOptsWithPorts returns opts extended with the ports of the given endpoints, if any.
+// The returned slice never shares its backing array with opts.
+func edgeOptsWithPorts(opts []EdgeOption, from, to endpoint) []EdgeOption {
+	opts = slices.Clip(opts)
+	if from.port != nil {
+		opts = append(opts, FromPort(from.port))
+	}
+	if to.port != nil {
+		opts = append(opts, ToPort(to.port))
+	}
+	return opts
 }
 
 // parseEdgeEndpoint parses an edge endpoint, which can be either:
-// - A node ID (returns single-element list)
-// - A subgraph (returns all node IDs in the subgraph)
-func (p *Parser) parseEdgeEndpoint(g *Graph) ([]string, error) {
+// - A node reference, with an optional port (returns single-element list)
+// - A subgraph (returns all nodes in the subgraph, without ports)
+func (p *Parser) parseEdgeEndpoint(g *Graph) ([]endpoint, error) {
 	// Check if this is a subgraph
 	if p.matchKeyword("subgraph") || p.match(TokenLBrace) {
 		// Parse the subgraph
@@ -664,29 +923,29 @@ func (p *Parser) parseEdgeEndpoint(g *Graph) ([]string, error) {
 			return nil, err
 		}
 
-		// Collect all node IDs from the subgraph
+		// Collect all nodes from the subgraph
 		nodes := sg.Nodes()
-		nodeIDs := make([]string, len(nodes))
+		endpoints := make([]endpoint, len(nodes))
 		for i, node := range nodes {
-			nodeIDs[i] = node.ID()
+			endpoints[i] = endpoint{id: node.ID()}
 		}
 
-		return nodeIDs, nil
+		return endpoints, nil
 	}
 
-	// Otherwise, parse a single node ID
-	id, err := p.parseID()
+	// Otherwise, parse a single node reference
+	ref, err := p.parseNodeRef()
 	if err != nil {
 		return nil, err
 	}
 
-	return []string{id}, nil
+	return []endpoint{ref}, nil
 }
 
 // parseNodeStmt parses a node statement: nodeID [attributes].
 func (p *Parser) parseNodeStmt(g *Graph, id string) error {
 	// Parse optional attributes
-	var attrs map[string]string
+	var attrs map[string]attrValue
 	if p.match(TokenLBracket) {
 		var err error
 		attrs, err = p.parseAttrList()
@@ -695,33 +954,103 @@ func (p *Parser) parseNodeStmt(g *Graph, id string) error {
 		}
 	}
 
-	// Create node with attributes
-	nodeOpts := p.mapNodeAttributes(attrs)
-	node := NewNode(id, nodeOpts...)
-	return g.AddNode(node)
+	n := p.declareNode(g, id, attrs)
+	p.claimComment(&n.comment)
+	return nil
+}
+
+// pushScope starts the defaults scope of a graph or subgraph, inheriting the enclosing
+// scope's defaults.
+func (p *Parser) pushScope() {
+	scope := defaultsScope{
+		node: make(map[string]attrValue),
+		edge: make(map[string]attrValue),
+	}
+	if n := len(p.scopes); n > 0 {
+		maps.Copy(scope.node, p.scopes[n-1].node)
+		maps.Copy(scope.edge, p.scopes[n-1].edge)
+	}
+	p.scopes = append(p.scopes, scope)
+}
+
+// popScope ends the innermost defaults scope.
+func (p *Parser) popScope() {
+	p.scopes = p.scopes[:len(p.scopes)-1]
+}
+
+// resolveNode returns the graph's node with the given ID. If this is the node's first
+// mention, it is created with the node defaults of the current scope.
+func (p *Parser) resolveNode(g *Graph, id string) *Node {
+	if n := g.GetNode(id); n != nil {
+		return n
+	}
+
+	var defaults map[string]attrValue
+	if len(p.scopes) > 0 {
+		defaults = p.scopes[len(p.scopes)-1].node
+	}
+
+	n := NewNode(id, p.mapNodeAttributes(defaults)...)
+	_ = g.AddNode(n) // Safe to ignore - n is never nil
+	return n
+}
+
+// declareNode applies the attributes of a node statement to the node with the given ID,
+// merging them into the existing node if it has already been mentioned, as Graphviz does.
+func (p *Parser) declareNode(g *Graph, id string, attrs map[string]attrValue) *Node {
+	n := p.resolveNode(g, id)
+
+	// A new label replaces the existing one, whatever its kind
+	if _, ok := attrs["label"]; ok {
+		n.attrs.label = nil
+		n.attrs.htmlLabel = nil
+		n.attrs.rawHTMLLabel = nil
+		n.attrs.recordLabel = nil
+	}
+
+	for _, opt := range p.mapNodeAttributes(attrs) {
+		opt.applyNode(n.attrs)
+	}
+
+	if n.attrs.htmlLabel != nil {
+		n.attrs.htmlLabel.setNodeContext(id)
+	}
+
+	return n
+}
+
+// edgeAttrsInScope returns attrs layered over the edge defaults of the current scope.
+func (p *Parser) edgeAttrsInScope(attrs map[string]attrValue) map[string]attrValue {
+	if len(p.scopes) == 0 {
+		return attrs
+	}
+
+	scoped := maps.Clone(p.scopes[len(p.scopes)-1].edge)
+	maps.Copy(scoped, attrs)
+	return scoped
 }
 
 // parseEdgeStmtWithNodes parses an edge statement where endpoints can be subgraphs.
-// Each endpoint is a list of node IDs (single node or all nodes from a subgraph).
+// Each endpoint is a list of nodes (single node or all nodes from a subgraph).
 // Creates edges between all combinations of nodes at adjacent endpoints.
-func (p *Parser) parseEdgeStmtWithNodes(g *Graph, firstNodes []string) error {
-	// Store all endpoints as lists of node IDs
-	endpoints := [][]string{firstNodes}
+func (p *Parser) parseEdgeStmtWithNodes(g *Graph, firstNodes []endpoint) error {
+	// Store all endpoints as lists of nodes
+	endpoints := [][]endpoint{firstNodes}
 
 	// Parse edge chain
 	for p.match(TokenArrow) {
 		p.advance() // consume arrow
 
 		// Parse next endpoint (node or subgraph)
-		nodeIDs, err := p.parseEdgeEndpoint(g)
+		nodes, err := p.parseEdgeEndpoint(g)
 		if err != nil {
 			return err
 		}
-		endpoints = append(endpoints, nodeIDs)
+		endpoints = append(endpoints, nodes)
 	}
 
 	// Parse optional edge attributes
-	var attrs map[string]string
+	var attrs map[string]attrValue
 	if p.match(TokenLBracket) {
 		var err error
 		attrs, err = p.parseAttrList()
@@ -731,19 +1060,25 @@ func (p *Parser) parseEdgeStmtWithNodes(g *Graph, firstNodes []string) error {
 	}
 
 	// Create edges between each pair of adjacent endpoints
-	edgeOpts := p.mapEdgeAttributes(attrs)
+	edgeOpts := p.mapEdgeAttributes(p.edgeAttrsInScope(attrs))
 	for i := 0; i < len(endpoints)-1; i++ {
 		fromNodes := endpoints[i]
 		toNodes := endpoints[i+1]
 
 		// Create edges from all nodes in fromNodes to all nodes in toNodes
-		for _, fromID := range fromNodes {
-			for _, toID := range toNodes {
-				from := NewNode(fromID)
-				to := NewNode(toID)
-				if _, err := g.AddEdge(from, to, edgeOpts...); err != nil {
+		for _, fromRef := range fromNodes {
+			for _, toRef := range toNodes {
+				from := p.resolveNode(g, fromRef.id)
+				to := p.resolveNode(g, toRef.id)
+				opts := edgeOptsWithPorts(edgeOpts, fromRef, toRef)
+				// Self-loops in strict graphs are dropped, leaving edge nil
+				edge, err := g.AddEdge(from, to, opts...)
+				if err != nil {
 					return err
 				}
+				if edge != nil {
+					p.claimComment(&edge.comment)
+				}
 			}
 		}
 	}
@@ -752,7 +1087,7 @@ func (p *Parser) parseEdgeStmtWithNodes(g *Graph, firstNodes []string) error {
 }
 
 // mapNodeAttributes maps parsed attributes to NodeOption functions.
-func (p *Parser) mapNodeAttributes(attrs map[string]string) []NodeOption {
+func (p *Parser) mapNodeAttributes(attrs map[string]attrValue) []NodeOption {
 	if attrs == nil {
 		return nil
 	}
@@ -761,24 +1096,24 @@ func (p *Parser) mapNodeAttributes(attrs map[string]string) []NodeOption {
 
 	// Map known attributes
 	if label, ok := attrs["label"]; ok {
-		opts = append(opts, WithLabel(label))
+		opts = append(opts, nodeLabelOption(label))
 	}
 	if shape, ok := attrs["shape"]; ok {
-		opts = append(opts, withShape(Shape(shape)))
+		opts = append(opts, withShape(Shape(shape.value)))
 	}
 	if color, ok := attrs["color"]; ok {
-		opts = append(opts, WithColor(color))
+		opts = append(opts, WithColor(color.value))
 	}
 	if fillcolor, ok := attrs["fillcolor"]; ok {
-		opts = append(opts, WithFillColor(fillcolor))
+		opts = append(opts, WithFillColor(fillcolor.value))
 	}
 	if fontname, ok := attrs["fontname"]; ok {
-		opts = append(opts, WithFontName(fontname))
+		opts = append(opts, WithFontName(fontname.value))
 	}
 	if fontsize, ok := attrs["fontsize"]; ok {
 		// Parse fontsize as float
 		var size float64
-		if _, err := fmt.Sscanf(fontsize, "%f", &size); err == nil && size > 0 {
+		if _, err := fmt.Sscanf(fontsize.value, "%f", &size); err == nil && size > 0 {
 			opts = append(opts, WithFontSize(size))
 		}
 	}
@@ -790,15 +1125,30 @@ func (p *Parser) mapNodeAttributes(attrs map[string]string) []NodeOption {
 	}
 	for key, value := range attrs {
 		if !knownAttrs[key] {
-			opts = append(opts, WithNodeAttribute(key, value))
+			opts = append(opts, WithNodeAttribute(key, value.value))
 		}
 	}
 
 	return opts
 }
 
+// nodeLabelOption returns the option for a parsed node label. HTML labels holding a
+// single table are parsed into an HTMLLabel so their ports can be used by edges;
+// other HTML labels are kept verbatim as raw HTML.
+func nodeLabelOption(label attrValue) NodeOption {
+	if !label.html {
+		return WithLabel(label.value)
+	}
+
+	if table, ok := parseHTMLTable(label.value); ok {
+		return WithHTMLLabel(table)
+	}
+
+	return WithRawHTMLLabel("<" + label.value + ">")
+}
+
 // mapEdgeAttributes maps parsed attributes to EdgeOption functions.
-func (p *Parser) mapEdgeAttributes(attrs map[string]string) []EdgeOption {
+func (p *Parser) mapEdgeAttributes(attrs map[string]attrValue) []EdgeOption {
 	if attrs == nil {
 		return nil
 	}
@@ -807,23 +1157,23 @@ func (p *Parser) mapEdgeAttributes(attrs map[string]string) []EdgeOption {
 
 	// Map known attributes
 	if label, ok := attrs["label"]; ok {
-		opts = append(opts, WithEdgeLabel(label))
+		opts = append(opts, WithEdgeLabel(label.value))
 	}
 	if color, ok := attrs["color"]; ok {
-		opts = append(opts, WithEdgeColor(color))
+		opts = append(opts, WithEdgeColor(color.value))
 	}
 	if style, ok := attrs["style"]; ok {
-		opts = append(opts, WithEdgeStyle(EdgeStyle(style)))
+		opts = append(opts, WithEdgeStyle(EdgeStyle(style.value)))
 	}
 	if arrowhead, ok := attrs["arrowhead"]; ok {
-		opts = append(opts, WithArrowHead(ArrowType(arrowhead)))
+		opts = append(opts, WithArrowHead(ArrowType(arrowhead.value)))
 	}
 	if arrowtail, ok := attrs["arrowtail"]; ok {
-		opts = append(opts, WithArrowTail(ArrowType(arrowtail)))
+		opts = append(opts, WithArrowTail(ArrowType(arrowtail.value)))
 	}
 	if weight, ok := attrs["weight"]; ok {
 		var w float64
-		if _, err := fmt.Sscanf(weight, "%f", &w); err == nil && w > 0 {
+		if _, err := fmt.Sscanf(weight.value, "%f", &w); err == nil && w > 0 {
 			opts = append(opts, WithWeight(w))
 		}
 	}
@@ -835,23 +1185,36 @@ func (p *Parser) mapEdgeAttributes(attrs map[string]string) []EdgeOption {
 	}
 	for key, value := range attrs {
 		if !knownAttrs[key] {
-			opts = append(opts, WithEdgeAttribute(key, value))
+			opts = append(opts, WithEdgeAttribute(key, value.value))
 		}
 	}
 
 	return opts
 }
 
-// applyDefaultAttrs applies default attributes to the graph.
-func (p *Parser) applyDefaultAttrs(g *Graph, keyword string, attrs map[string]string) error {
+// applyDefaultAttrs applies default attributes to the graph. Node and edge defaults set
+// after the first node or edge only apply to those declared later, so they are kept in
+// the root scope rather than becoming the graph's defaults.
+func (p *Parser) applyDefaultAttrs(g *Graph, keyword string, attrs map[string]attrValue) error {
+	p.appendComment(&g.comment)
+	scope := p.scopes[len(p.scopes)-1]
+
 	switch keyword {
 	case "node":
+		if len(g.nodeOrder) > 0 {
+			maps.Copy(scope.node, attrs)
+			return nil
+		}
 		opts := p.mapNodeAttributes(attrs)
 		// Apply to graph's default node attributes
 		for _, opt := range opts {
 			opt.applyNode(g.DefaultNodeAttrs())
 		}
 	case "edge":
+		if g.edges.len() > 0 {
+			maps.Copy(scope.edge, attrs)
+			return nil
+		}
 		opts := p.mapEdgeAttributes(attrs)
 		// Apply to graph's default edge attributes
 		for _, opt := range opts {
@@ -860,12 +1223,95 @@ func (p *Parser) applyDefaultAttrs(g *Graph, keyword string, attrs map[string]st
 	case "graph":
 		// Apply graph attributes
 		for key, value := range attrs {
-			g.Attrs().setCustom(key, value)
+			g.Attrs().setCustom(key, value.value)
 		}
 	}
 	return nil
 }
 
+// applySubgraphDefaultAttrs applies a node, edge or graph [...] statement made inside a
+// subgraph. Node and edge defaults are recorded only on the subgraph, whose block is where
+// its nodes and edges are written, and replace any inherited scoped defaults for the same
+// attributes. Defaults set after the block already holds nodes or edges only apply to
+// those declared later, so, as at the root, they are kept in the scope instead.
+func (p *Parser) applySubgraphDefaultAttrs(sg *Subgraph, keyword string, attrs map[string]attrValue) {
+	p.appendComment(&sg.comment)
+	scope := p.scopes[len(p.scopes)-1]
+
+	switch keyword {
+	case "node":
+		if subgraphHasNodes(sg) {
+			maps.Copy(scope.node, attrs)
+			return
+		}
+		for key := range attrs {
+			delete(scope.node, key)
+		}
+		for _, opt := range p.mapNodeAttributes(attrs) {
+			opt.applyNode(sg.DefaultNodeAttrs())
+		}
+	case "edge":
+		if subgraphHasEdges(sg) {
+			maps.Copy(scope.edge, attrs)
+			return
+		}
+		for key := range attrs {
+			delete(scope.edge, key)
+		}
+		for _, opt := range p.mapEdgeAttributes(attrs) {
+			opt.applyEdge(sg.DefaultEdgeAttrs())
+		}
+	case "graph":
+		applySubgraphAttrs(sg, attrs)
+	}
+}
+
+// subgraphHasNodes reports whether the subgraph or any of its nested subgraphs has a node.
+func subgraphHasNodes(sg *Subgraph) bool {
+	return len(sg.nodeOrder) > 0 || slices.ContainsFunc(sg.subgraphs, subgraphHasNodes)
+}
+
+// subgraphHasEdges reports whether the subgraph or any of its nested subgraphs has an edge.
+func subgraphHasEdges(sg *Subgraph) bool {
+	return sg.edges.len() > 0 || slices.ContainsFunc(sg.subgraphs, subgraphHasEdges)
+}
+
+// applySubgraphAttrs sets parsed graph attributes on a subgraph.
+func applySubgraphAttrs(sg *Subgraph, attrs map[string]attrValue) {
+	for key, attr := range attrs {
+		switch key {
+		case "label":
+			sg.SetLabel(attr.value)
+		case "style":
+			sg.SetStyle(attr.value)
+		case "color":
+			sg.SetColor(attr.value)
+		case "fillcolor":
+			sg.SetFillColor(attr.value)
+		case "rank":
+			sg.SetRank(Rank(attr.value))
+		default:
+			sg.SetAttribute(key, attr.value)
+		}
+	}
+}
+
+// parseAttrStmt parses the value of an attribute statement (ID '=' ID) whose name has
+// already been consumed, returning it as a single-entry attribute list.
+func (p *Parser) parseAttrStmt(name string) (map[string]attrValue, error) {
+	if err := p.expect(TokenEqual); err != nil {
+		return nil, err
+	}
+
+	html := p.match(TokenHTML)
+	value, err := p.parseID()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]attrValue{name: {value: value, html: html}}, nil
+}
+
 // parseSubgraph parses a subgraph statement.
 // Syntax: [subgraph [ID]] { stmt_list }
 // Returns the created subgraph.
@@ -889,10 +1335,13 @@ func (p *Parser) parseSubgraph(g *Graph) (*Subgraph, error) {
 
 	// Parse subgraph contents and create subgraph
 	var parseErr error
+	p.pushScope()
 	sg := g.Subgraph(name, func(s *Subgraph) {
+		p.claimComment(&s.comment)
 		// Parse statements into this subgraph
-		parseErr = p.parseSubgraphStmts(s, g)
+		parseErr = p.parseSubgraphStmts(s)
 	})
+	p.popScope()
 
 	if parseErr != nil {
 		return nil, parseErr
@@ -907,8 +1356,8 @@ func (p *Parser) parseSubgraph(g *Graph) (*Subgraph, error) {
 }
 
 // parseSubgraphStmts parses a list of statements within a subgraph.
-// Nodes and edges are added to the subgraph, while default attributes affect the parent graph.
-func (p *Parser) parseSubgraphStmts(sg *Subgraph, g *Graph) error {
+// Nodes, edges, attributes and default attributes are all added to the subgraph.
+func (p *Parser) parseSubgraphStmts(sg *Subgraph) error {
 	for !p.match(TokenRBrace) && !p.match(TokenEOF) {
 		// Skip optional semicolons
 		if p.match(TokenSemi) {
@@ -917,9 +1366,11 @@ func (p *Parser) parseSubgraphStmts(sg *Subgraph, g *Graph) error {
 		}
 
 		// Parse statement in subgraph context
-		if err := p.parseSubgraphStmt(sg, g); err != nil {
+		p.comment = p.leadingComment()
+		if err := p.parseSubgraphStmt(sg); err != nil {
 			return err
 		}
+		p.comment = ""
 
 		// Skip optional trailing semicolon
 		if p.match(TokenSemi) {
@@ -930,10 +1381,10 @@ func (p *Parser) parseSubgraphStmts(sg *Subgraph, g *Graph) error {
 }
 
 // parseSubgraphStmt parses a single statement within a subgraph context.
-func (p *Parser) parseSubgraphStmt(sg *Subgraph, g *Graph) error {
+func (p *Parser) parseSubgraphStmt(sg *Subgraph) error {
 	// Check for keywords: node, edge, graph
 	if p.matchKeyword("node") || p.matchKeyword("edge") || p.matchKeyword("graph") {
-		// Default attribute statements - these affect the parent graph
+		// Default attribute statements - scoped to this subgraph
 		keyword := p.current.Value
 		p.advance()
 		if p.match(TokenLBracket) {
@@ -941,8 +1392,7 @@ func (p *Parser) parseSubgraphStmt(sg *Subgraph, g *Graph) error {
 			if err != nil {
 				return err
 			}
-			// Apply default attributes to parent graph
-			return p.applyDefaultAttrs(g, keyword, attrs)
+			p.applySubgraphDefaultAttrs(sg, keyword, attrs)
 		}
 		return nil
 	}
@@ -982,9 +1432,12 @@ func (p *Parser) parseNestedSubgraph(parent *Subgraph) error {
 
 	// Parse nested subgraph contents
 	var parseErr error
+	p.pushScope()
 	parent.Subgraph(name, func(s *Subgraph) {
-		parseErr = p.parseSubgraphStmts(s, parent.parent)
+		p.claimComment(&s.comment)
+		parseErr = p.parseSubgraphStmts(s)
 	})
+	p.popScope()
 
 	if parseErr != nil {
 		return parseErr
@@ -1000,25 +1453,36 @@ func (p *Parser) parseNestedSubgraph(parent *Subgraph) error {
 
 // parseNodeOrEdgeStmtInSubgraph parses a node or edge statement and adds it to the subgraph.
 func (p *Parser) parseNodeOrEdgeStmtInSubgraph(sg *Subgraph) error {
-	// Parse first ID
-	id, err := p.parseID()
+	// Parse first node reference
+	first, err := p.parseNodeRef()
 	if err != nil {
 		return err
 	}
 
+	// Check if this is a subgraph attribute statement (ID '=' ID)
+	if p.match(TokenEqual) && first.port == nil {
+		attrs, err := p.parseAttrStmt(first.id)
+		if err != nil {
+			return err
+		}
+		p.appendComment(&sg.comment)
+		applySubgraphAttrs(sg, attrs)
+		return nil
+	}
+
 	// Check if this is an edge statement (next token is arrow)
 	if p.match(TokenArrow) {
-		return p.parseEdgeStmtInSubgraph(sg, id)
+		return p.parseEdgeStmtInSubgraph(sg, first)
 	}
 
-	// Otherwise, it's a node statement
-	return p.parseNodeStmtInSubgraph(sg, id)
+	// Otherwise, it's a node statement; like Graphviz, any port is ignored
+	return p.parseNodeStmtInSubgraph(sg, first.id)
 }
 
 // parseNodeStmtInSubgraph parses a node statement and adds it to the subgraph.
 func (p *Parser) parseNodeStmtInSubgraph(sg *Subgraph, id string) error {
 	// Parse optional attributes
-	var attrs map[string]string
+	var attrs map[string]attrValue
 	if p.match(TokenLBracket) {
 		var err error
 		attrs, err = p.parseAttrList()
@@ -1027,30 +1491,29 @@ func (p *Parser) parseNodeStmtInSubgraph(sg *Subgraph, id string) error {
 		}
 	}
 
-	// Create node with attributes
-	nodeOpts := p.mapNodeAttributes(attrs)
-	node := NewNode(id, nodeOpts...)
-	return sg.AddNode(node)
+	n := p.declareNode(sg.parent, id, attrs)
+	p.claimComment(&n.comment)
+	return sg.AddNode(n)
 }
 
 // parseEdgeStmtInSubgraph parses an edge statement and adds it to the subgraph.
-func (p *Parser) parseEdgeStmtInSubgraph(sg *Subgraph, firstID string) error {
-	nodes := []string{firstID}
+func (p *Parser) parseEdgeStmtInSubgraph(sg *Subgraph, first endpoint) error {
+	nodes := []endpoint{first}
 
 	// Parse edge chain
 	for p.match(TokenArrow) {
 		p.advance() // consume arrow
 
-		// Parse next node ID
-		id, err := p.parseID()
+		// Parse next node reference
+		ref, err := p.parseNodeRef()
 		if err != nil {
 			return err
 		}
-		nodes = append(nodes, id)
+		nodes = append(nodes, ref)
 	}
 
 	// Parse optional edge attributes
-	var attrs map[string]string
+	var attrs map[string]attrValue
 	if p.match(TokenLBracket) {
 		var err error
 		attrs, err = p.parseAttrList()
@@ -1060,13 +1523,19 @@ func (p *Parser) parseEdgeStmtInSubgraph(sg *Subgraph, firstID string) error {
 	}
 
 	// Create edges for the chain
-	edgeOpts := p.mapEdgeAttributes(attrs)
+	edgeOpts := p.mapEdgeAttributes(p.edgeAttrsInScope(attrs))
 	for i := 0; i < len(nodes)-1; i++ {
-		from := NewNode(nodes[i])
-		to := NewNode(nodes[i+1])
-		if _, err := sg.AddEdge(from, to, edgeOpts...); err != nil {
+		from := p.resolveNode(sg.parent, nodes[i].id)
+		to := p.resolveNode(sg.parent, nodes[i+1].id)
+		opts := edgeOptsWithPorts(edgeOpts, nodes[i], nodes[i+1])
+		// Self-loops in strict graphs are dropped, leaving edge nil
+		edge, err := sg.AddEdge(from, to, opts...)
+		if err != nil {
 			return err
 		}
+		if edge != nil {
+			p.claimComment(&edge.comment)
+		}
 	}
 
 	return nil
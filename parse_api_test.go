@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseString_SimpleGraph(t *testing.T) {
@@ -384,3 +385,206 @@ func TestParseFile_ClusterFixture(t *testing.T) {
 		asrt.True(len(sg.Nodes()) > 0, "Subgraph should have nodes")
 	}
 }
+
+func TestParse_RoundTrip_EdgePorts(t *testing.T) {
+	asrt := assert.New(t)
+
+	input := `digraph {
+	A:p1:n -> B:p2;
+	B -> C:"in":sw;
+}`
+
+	g1, err := ParseString(input)
+	asrt.NoError(err, "Should parse edge ports without error")
+
+	dot := g1.String()
+	asrt.Contains(dot, `"A":"p1":n -> "B":"p2"`)
+	asrt.Contains(dot, `"B" -> "C":"in":sw`)
+
+	g2, err := ParseString(dot)
+	asrt.NoError(err, "Should parse generated DOT without error")
+	asrt.Equal(dot, g2.String(), "Ports should survive a round trip")
+}
+
+func TestParse_RoundTrip_HTMLLabels(t *testing.T) {
+	asrt := assert.New(t)
+
+	g1 := NewGraph(Directed)
+	out := Cell(Text("Out").Bold()).Port("out")
+	a := NewNode("A", WithHTMLLabel(HTMLTable(Row(Cell(Text("A")), out)).Border(0)))
+	b := NewNode("B", WithRawHTMLLabel("<<i>raw</i>>"))
+	_, _ = g1.AddEdge(a, b, FromPort(out.GetPort()))
+
+	dot := g1.String()
+	g2, err := ParseString(dot)
+
+	asrt.NoError(err, "Should parse generated DOT without error")
+	asrt.Equal(dot, g2.String(), "HTML labels should survive a round trip")
+	asrt.NotNil(g2.GetNode("A").Attrs().HTMLLabel())
+	asrt.Same(g2.GetNode("A").Attrs().HTMLLabel().GetPort("out"), g2.Edges()[0].Attrs().FromPort())
+}
+
+func TestParse_RoundTrip_RecordLabels(t *testing.T) {
+	asrt := assert.New(t)
+
+	g1 := NewGraph(Directed)
+	out := Field("out|put").Port("out")
+	a := NewNode("A", WithRecordLabel(Record(Field("in").Port("in"), FieldGroup(out, Field("{x}")))))
+	_, _ = g1.AddEdge(a, NewNode("B"), FromPort(out.GetPort()))
+
+	dot := g1.String()
+	g2, err := ParseString(dot)
+
+	asrt.NoError(err, "Should parse generated DOT without error")
+	asrt.Equal(dot, g2.String(), "Record labels should survive a round trip")
+	asrt.Same(g2.GetNode("A").Attrs().RecordLabel().GetPort("out"), g2.Edges()[0].Attrs().FromPort())
+}
+
+func TestParse_RoundTrip_LabelEscapes(t *testing.T) {
+	t.Run("keeps escapes in record labels", func(t *testing.T) {
+		asrt := assert.New(t)
+		req := require.New(t)
+
+		g1, err := ParseString(`digraph { r [shape=record, label="<f0> left\l|a\|b|\{c\}"] }`)
+		req.NoError(err)
+
+		dot := g1.String()
+		asrt.Contains(dot, `label="<f0> left\l | a\\|b | \\{c\\}"`, "expected \\l to be written once")
+
+		g2, err := ParseString(dot)
+		req.NoError(err, "Should parse generated DOT without error")
+		asrt.Equal(dot, g2.String(), "Record label escapes should survive a round trip")
+		asrt.Equal(`<f0> left\l | a\|b | \{c\}`, g2.GetNode("r").Attrs().RecordLabel().String())
+	})
+
+	t.Run("keeps escapes in plain labels", func(t *testing.T) {
+		asrt := assert.New(t)
+		req := require.New(t)
+
+		g1, err := ParseString(`digraph { a [label="x\ly\l"]; a -> b [label="\N\rto \H"] }`)
+		req.NoError(err)
+
+		dot := g1.String()
+		asrt.Contains(dot, `label="x\ly\l"`, "expected node label escapes to be written once")
+		asrt.Contains(dot, `label="\N\rto \H"`, "expected edge label escapes to be written once")
+
+		g2, err := ParseString(dot)
+		req.NoError(err, "Should parse generated DOT without error")
+		asrt.Equal(dot, g2.String(), "Label escapes should survive a round trip")
+	})
+
+	t.Run("still escapes other backslashes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		_ = g.AddNode(NewNode(`\root`, WithLabel(`C:\dir\files\l`)))
+
+		dot := g.String()
+		asrt.Contains(dot, `"\\root"`, "expected node IDs to escape every backslash")
+		asrt.Contains(dot, `label="C:\\dir\\files\l"`, "expected only the label escape to be kept")
+	})
+}
+
+func TestParse_RoundTrip_StyledCluster(t *testing.T) {
+	asrt := assert.New(t)
+
+	g1 := NewGraph(Directed)
+	g1.Subgraph("cluster_db", func(s *Subgraph) {
+		s.SetLabel("Databases")
+		s.SetStyle("filled")
+		s.SetDefaultNodeAttrs(WithBoxShape())
+		s.SetDefaultEdgeAttrs(WithEdgeColor("gray"))
+		_ = s.AddNode(NewNode("primary"))
+	})
+
+	dot := g1.String()
+	g2, err := ParseString(dot)
+	asrt.NoError(err, "Should parse generated DOT without error")
+
+	sg := g2.Subgraphs()[0]
+	asrt.Equal("Databases", sg.Attrs().Label())
+	asrt.Equal("filled", sg.Attrs().Style())
+	asrt.Equal(ShapeBox, sg.DefaultNodeAttrs().Shape())
+	asrt.Equal("gray", sg.DefaultEdgeAttrs().Color())
+
+	g3, err := ParseString(g2.String())
+	asrt.NoError(err)
+	asrt.Equal(g2.String(), g3.String(), "Styled clusters should be stable across round trips")
+}
+
+func TestParse_Comments(t *testing.T) {
+	t.Run("attaches leading comments to the following statement", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g, err := ParseString(`// Service dependencies
+digraph G {
+	// The public entry point
+	api [shape=box];
+	/* Reads go to the replica */
+	api -> replica -> primary;
+	// Storage tier
+	subgraph cluster_db {
+		// Holds the source of truth
+		primary;
+	}
+	node [color=gray]; // trailing, ignored
+}`)
+
+		asrt.NoError(err)
+		asrt.Equal("Service dependencies", g.Comment())
+		asrt.Equal("The public entry point", g.GetNode("api").Comment())
+		asrt.Equal("Reads go to the replica", g.Edges()[0].Comment())
+		asrt.Empty(g.Edges()[1].Comment(), "only the first edge of a chain gets the comment")
+		asrt.Equal("Storage tier", g.Subgraphs()[0].Comment())
+		asrt.Equal("Holds the source of truth", g.GetNode("primary").Comment())
+		asrt.Empty(g.GetNode("replica").Comment())
+	})
+
+	t.Run("keeps comments on attribute statements with their graph or subgraph", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g, err := ParseString(`// Service dependencies
+digraph G {
+	// Left to right reads better
+	rankdir=LR;
+	// Everything is a box
+	node [shape=box];
+	// Storage tier
+	subgraph cluster_db {
+		// Highlight the tier
+		color=blue;
+		/* Dashed links inside */
+		edge [style=dashed];
+		primary;
+	}
+}`)
+
+		asrt.NoError(err)
+		asrt.Equal("Service dependencies\nLeft to right reads better\nEverything is a box", g.Comment())
+		asrt.Equal("Storage tier\nHighlight the tier\nDashed links inside", g.Subgraphs()[0].Comment())
+		asrt.Empty(g.GetNode("primary").Comment())
+	})
+
+	t.Run("comments survive a round trip", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g1 := NewGraph(Directed)
+		g1.SetComment("Generated\ndo not edit")
+		a, b := NewNode("A"), NewNode("B")
+		a.SetComment("start here")
+		g1.Subgraph("cluster_0", func(s *Subgraph) {
+			s.SetComment("group")
+			_ = s.AddNode(b)
+		})
+		e, _ := g1.AddEdge(a, b)
+		e.SetComment("A calls B")
+
+		g2, err := ParseString(g1.String())
+		asrt.NoError(err)
+		asrt.Equal(g1.String(), g2.String())
+
+		g3, err := ParseString(NewDOTPrinter(WithBlockComments()).Print(g1))
+		asrt.NoError(err)
+		asrt.Equal(g1.String(), g3.String(), "block comments should round trip too")
+	})
+}
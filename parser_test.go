@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParse_EmptyDigraph(t *testing.T) {
@@ -59,6 +60,19 @@ func TestParse_StrictGraph(t *testing.T) {
 	}
 }
 
+func TestParse_StrictGraph_MergesEdges(t *testing.T) {
+	asrt := assert.New(t)
+
+	input := `strict digraph { A -> B [color=red]; A -> B [label="x"]; A -> A; }`
+	parser := newParser(input)
+	g, err := parser.parseGraph()
+
+	asrt.NoError(err, "Should parse strict graph with duplicates and self-loops")
+	asrt.Len(g.Edges(), 1, "Duplicate edges and self-loops should be collapsed")
+	asrt.Equal("red", g.Edges()[0].Attrs().Color(), "Earlier attributes should be kept")
+	asrt.Equal("x", g.Edges()[0].Attrs().Label(), "Later attributes should be merged")
+}
+
 func TestParse_NamedGraph(t *testing.T) {
 	asrt := assert.New(t)
 
@@ -654,9 +668,15 @@ func TestParse_Subgraph_DefaultAttributes(t *testing.T) {
 	asrt.NoError(err, "Should parse subgraph with default attributes without error")
 	asrt.NotNil(g, "Graph should not be nil")
 
-	// Default attributes set in subgraph should affect parent graph
-	asrt.Equal("circle", string(g.DefaultNodeAttrs().Shape()), "Default node shape should be circle")
-	asrt.Equal("blue", g.DefaultEdgeAttrs().Color(), "Default edge color should be blue")
+	// Default attributes set in subgraph apply to its nodes and edges, not the parent graph
+	asrt.Empty(g.DefaultNodeAttrs().Shape(), "Graph default node shape should be unset")
+	asrt.Empty(g.DefaultEdgeAttrs().Color(), "Graph default edge color should be unset")
+	sg := g.Subgraphs()[0]
+	asrt.Equal(ShapeCircle, sg.DefaultNodeAttrs().Shape(), "Subgraph should record its default shape")
+	asrt.Equal("blue", sg.DefaultEdgeAttrs().Color(), "Subgraph should record its default color")
+	asrt.Equal([]string{"A", "B"}, nodeIDs(sg.Nodes()), "Nodes should be written in the subgraph that styles them")
+	asrt.Empty(g.GetNode("A").Attrs().Shape(), "Subgraph defaults should not be copied onto nodes")
+	asrt.Empty(g.Edges()[0].Attrs().Color(), "Subgraph defaults should not be copied onto edges")
 }
 
 func TestParse_SubgraphAsEdgeEndpoint_SubgraphToNode(t *testing.T) {
@@ -828,3 +848,473 @@ func TestParse_SubgraphAsEdgeEndpoint_WithAttributes(t *testing.T) {
 		asrt.Equal("red", edge.Attrs().Color(), "All edges should have color=red")
 	}
 }
+
+func TestParse_EdgePorts(t *testing.T) {
+	t.Run("port and compass point", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph { A:p1:n -> B:"p 2"; }`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err, "Should parse edge ports without error")
+		asrt.Len(g.Edges(), 1)
+
+		edge := g.Edges()[0]
+		fromPort := edge.Attrs().FromPort()
+		asrt.NotNil(fromPort, "Edge should have a from port")
+		asrt.Equal("p1", fromPort.ID())
+		asrt.Equal("A", fromPort.NodeID(), "From port should be wired to A")
+		asrt.Equal(CompassN, fromPort.Compass())
+
+		toPort := edge.Attrs().ToPort()
+		asrt.NotNil(toPort, "Edge should have a to port")
+		asrt.Equal("p 2", toPort.ID())
+		asrt.Equal("B", toPort.NodeID(), "To port should be wired to B")
+		asrt.Empty(toPort.Compass())
+	})
+
+	t.Run("ports in edge chains", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph { A:out -> B:in -> C }`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		asrt.Len(g.Edges(), 2)
+		asrt.Equal("out", g.Edges()[0].Attrs().FromPort().ID())
+		asrt.Equal("in", g.Edges()[0].Attrs().ToPort().ID())
+		asrt.Equal("in", g.Edges()[1].Attrs().FromPort().ID(), "Middle endpoint port should apply to both edges")
+		asrt.Nil(g.Edges()[1].Attrs().ToPort())
+	})
+
+	t.Run("ports in subgraphs", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph { subgraph cluster_0 { A:p1 -> B:p2:sw [color=red] } }`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		asrt.Len(g.Edges(), 1)
+		edge := g.Edges()[0]
+		asrt.Equal("p1", edge.Attrs().FromPort().ID())
+		asrt.Equal(CompassSW, edge.Attrs().ToPort().Compass())
+		asrt.Equal("red", edge.Attrs().Color())
+	})
+
+	t.Run("ports in node statements are ignored", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph { A:p1 [label="a"]; subgraph { B:p2:e } }`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		asrt.Equal([]string{"A", "B"}, nodeIDs(g.Nodes()))
+		asrt.Equal("a", g.GetNode("A").Attrs().Label())
+	})
+
+	t.Run("lone compass point", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph { A:se -> B:out; }`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		fromPort := g.Edges()[0].Attrs().FromPort()
+		asrt.Empty(fromPort.ID(), "Lone compass name should not be treated as a port")
+		asrt.Equal(CompassSE, fromPort.Compass())
+		asrt.Equal("A", fromPort.NodeID())
+		asrt.Equal("out", g.Edges()[0].Attrs().ToPort().ID())
+	})
+
+	t.Run("invalid compass point", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph { A:p1:up -> B }`)
+		_, err := parser.parseGraph()
+
+		asrt.ErrorContains(err, "invalid compass point")
+	})
+
+	t.Run("missing port after colon", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph { A: -> B }`)
+		_, err := parser.parseGraph()
+
+		asrt.Error(err, "Should fail when a port is missing after a colon")
+	})
+}
+
+func TestParse_HTMLLabels(t *testing.T) {
+	t.Run("table labels become HTMLLabel trees", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph {
+			A [label=<<table><tr><td port="out">Out</td></tr></table>>];
+			A:out -> B;
+		}`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err, "Should parse HTML label without error")
+		label := g.GetNode("A").Attrs().HTMLLabel()
+		asrt.NotNil(label, "Node should have an HTML label")
+		asrt.Empty(g.GetNode("A").Attrs().Label())
+
+		port := label.GetPort("out")
+		asrt.NotNil(port)
+		asrt.Equal("A", port.NodeID(), "Label port should be wired to its node")
+		asrt.Same(port, g.Edges()[0].Attrs().FromPort(), "Edge should use the label's port")
+	})
+
+	t.Run("edges declared before the label use its ports", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph {
+			A -> B:in:w;
+			B [label=<<table><tr><td port="in">In</td></tr></table>>];
+		}`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		toPort := g.Edges()[0].Attrs().ToPort()
+		asrt.Equal("in", toPort.ID())
+		asrt.Equal(CompassW, toPort.Compass())
+		asrt.Equal("B", toPort.NodeID())
+	})
+
+	t.Run("unsupported markup falls back to raw HTML", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph { A [label=<<b>bold</b> text>]; }`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		asrt.Nil(g.GetNode("A").Attrs().HTMLLabel())
+		asrt.Equal("<<b>bold</b> text>", g.GetNode("A").Attrs().RawHTMLLabel())
+	})
+
+	t.Run("quoted strings are not HTML", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph { A [label="<table></table>"]; }`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		asrt.Nil(g.GetNode("A").Attrs().HTMLLabel())
+		asrt.Equal("<table></table>", g.GetNode("A").Attrs().Label())
+	})
+}
+
+func TestParse_RecordLabels(t *testing.T) {
+	t.Run("record shapes get RecordLabels", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph {
+			A [shape=record, label="<f0> left|{<f1> mid|<f2> right}"];
+			B [shape=Mrecord, label="<in> in|out"];
+			A:f2 -> B:in;
+		}`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err, "Should parse record labels without error")
+
+		a := g.GetNode("A").Attrs()
+		asrt.NotNil(a.RecordLabel(), "Record node should have a RecordLabel")
+		asrt.Empty(a.Label())
+
+		port := a.RecordLabel().GetPort("f2")
+		asrt.NotNil(port)
+		asrt.Equal("A", port.NodeID(), "Record ports should be wired to their node")
+		asrt.Same(port, g.Edges()[0].Attrs().FromPort(), "Edge should use the record's port")
+
+		asrt.Equal(ShapeMrecord, g.GetNode("B").Attrs().Shape())
+		asrt.Same(g.GetNode("B").Attrs().RecordLabel().GetPort("in"), g.Edges()[0].Attrs().ToPort())
+	})
+
+	t.Run("shape from default node attributes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph { node [shape=record]; A [label="a|b"]; }`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		asrt.NotNil(g.GetNode("A").Attrs().RecordLabel())
+		asrt.Nil(g.GetNode("A").Attrs().shape, "Shape should stay inherited from the defaults")
+	})
+
+	t.Run("shape from subgraph default node attributes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph {
+			node [shape=record];
+			subgraph cluster_0 { node [shape=box]; A [label="a|b"]; subgraph { B [label="c|d"] } }
+			subgraph cluster_1 { node [shape=Mrecord]; C [label="e|f"] }
+		}`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		asrt.Nil(g.GetNode("A").Attrs().RecordLabel(), "Subgraph defaults should override graph defaults")
+		asrt.Nil(g.GetNode("B").Attrs().RecordLabel(), "Nested subgraphs should inherit subgraph defaults")
+		asrt.NotNil(g.GetNode("C").Attrs().RecordLabel())
+	})
+
+	t.Run("other shapes keep plain labels", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph { A [shape=box, label="a|b"]; B [shape=record, label="{a"]; }`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		asrt.Nil(g.GetNode("A").Attrs().RecordLabel())
+		asrt.Equal("a|b", g.GetNode("A").Attrs().Label())
+		asrt.Nil(g.GetNode("B").Attrs().RecordLabel(), "Invalid record syntax should stay a plain label")
+		asrt.Equal("{a", g.GetNode("B").Attrs().Label())
+	})
+}
+
+func TestParse_NodeIdentity(t *testing.T) {
+	t.Run("edge endpoints are the graph's nodes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph { A [color=red]; A -> B; subgraph { B -> C } }`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		asrt.Same(g.GetNode("A"), g.Edges()[0].From(), "Edge source should be the declared node")
+		asrt.Same(g.GetNode("B"), g.Edges()[0].To())
+		asrt.Same(g.GetNode("B"), g.Edges()[1].From(), "Subgraph edges should share nodes with the graph")
+		asrt.Same(g.GetNode("C"), g.Subgraphs()[0].Nodes()[1])
+		asrt.Equal("red", g.Edges()[0].From().Attrs().Color())
+	})
+
+	t.Run("later node statements merge attributes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph {
+			A -> B;
+			A [color=red, tooltip="first"];
+			A [shape=box, tooltip="second"];
+			subgraph cluster_0 { A [fillcolor=yellow] }
+		}`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		a := g.GetNode("A")
+		asrt.Same(a, g.Edges()[0].From(), "Merging should keep the node's identity")
+		asrt.Same(a, g.Subgraphs()[0].Nodes()[0])
+		asrt.Equal("red", a.Attrs().Color())
+		asrt.Equal(ShapeBox, a.Attrs().Shape())
+		asrt.Equal("yellow", a.Attrs().FillColor())
+		asrt.Equal("second", a.Attrs().Custom()["tooltip"])
+		asrt.Equal([]string{"A", "B"}, nodeIDs(g.Nodes()), "Node order should follow first mention")
+	})
+
+	t.Run("new labels replace labels of another kind", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph {
+			A [label=<<table><tr><td>x</td></tr></table>>];
+			A [label="plain"];
+		}`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		asrt.Nil(g.GetNode("A").Attrs().HTMLLabel())
+		asrt.Equal("plain", g.GetNode("A").Attrs().Label())
+	})
+
+	t.Run("subgraph defaults stay on the subgraph", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph {
+			A;
+			subgraph cluster_0 {
+				node [shape=box];
+				edge [color=red];
+				A -> B;
+				subgraph cluster_1 {
+					node [color=blue];
+					C [shape=circle];
+				}
+				D;
+			}
+			E;
+			E -> B;
+		}`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		outer := g.Subgraphs()[0]
+		inner := outer.Subgraphs()[0]
+		asrt.Equal(ShapeBox, outer.DefaultNodeAttrs().Shape())
+		asrt.Equal("red", outer.DefaultEdgeAttrs().Color())
+		asrt.Equal("blue", inner.DefaultNodeAttrs().Color())
+		for _, id := range []string{"A", "B", "D", "E"} {
+			asrt.Empty(g.GetNode(id).Attrs().Shape(), "Defaults should not be copied onto node %s", id)
+		}
+		asrt.Equal(ShapeCircle, g.GetNode("C").Attrs().Shape(), "Explicit attributes should be kept")
+		asrt.Empty(g.GetNode("C").Attrs().Color())
+		asrt.Equal([]string{"A", "B", "D"}, nodeIDs(outer.Nodes()))
+		asrt.Equal([]*Edge{g.Edges()[0]}, outer.Edges(), "Edges should be written in the subgraph that styles them")
+		asrt.Empty(g.Edges()[0].Attrs().Color())
+	})
+
+	t.Run("subgraph defaults after its first node only apply to later nodes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph {
+			node [color=gray];
+			A -> B;
+			edge [style=dotted];
+			subgraph cluster_0 {
+				node [color=red];
+				C -> D;
+				node [shape=box];
+				edge [color=blue];
+				E -> F;
+			}
+		}`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		sg := g.Subgraphs()[0]
+		asrt.Equal("red", sg.DefaultNodeAttrs().Color())
+		asrt.Empty(sg.DefaultNodeAttrs().Shape(), "Later defaults should not be recorded on the subgraph")
+		asrt.Empty(sg.DefaultEdgeAttrs().Color())
+		asrt.Empty(g.GetNode("C").Attrs().Shape())
+		asrt.Equal(ShapeBox, g.GetNode("E").Attrs().Shape())
+		asrt.Empty(g.GetNode("E").Attrs().Color(), "Subgraph defaults should replace inherited scoped ones")
+		asrt.Empty(g.Edges()[1].Attrs().Color())
+		asrt.Equal("blue", g.Edges()[2].Attrs().Color())
+		asrt.Equal(EdgeStyleDotted, g.Edges()[2].Attrs().Style(), "Root scope should still apply in subgraphs")
+	})
+
+	t.Run("scoped defaults survive a round trip unchanged", func(t *testing.T) {
+		asrt := assert.New(t)
+		req := require.New(t)
+
+		g, err := ParseString(`digraph {
+			A;
+			node [shape=box];
+			subgraph cluster_0 {
+				node [color=red];
+				edge [style=dashed];
+				subgraph cluster_1 { node [shape=circle]; C }
+				A -> B;
+			}
+		}`)
+		req.NoError(err)
+
+		reparsed, err := ParseString(g.String())
+		req.NoError(err)
+
+		asrt.Equal(g.String(), reparsed.String())
+		asrt.Empty(reparsed.GetNode("C").Attrs().Color(), "Round trips should not copy defaults onto nodes")
+		asrt.Empty(reparsed.GetNode("C").Attrs().Shape())
+		asrt.Equal(ShapeBox, reparsed.GetNode("B").Attrs().Shape())
+		asrt.Empty(reparsed.Edges()[0].Attrs().Style())
+	})
+}
+
+func TestParse_RootScopedDefaults(t *testing.T) {
+	t.Run("defaults before the first node become graph defaults", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph { node [shape=box]; edge [color=red]; A -> B }`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		asrt.Equal(ShapeBox, g.DefaultNodeAttrs().Shape())
+		asrt.Equal("red", g.DefaultEdgeAttrs().Color())
+		asrt.Empty(g.GetNode("A").Attrs().Shape(), "Graph defaults should not be copied onto nodes")
+		asrt.Empty(g.Edges()[0].Attrs().Color())
+	})
+
+	t.Run("later defaults only apply to later nodes and edges", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph {
+			A -> B;
+			node [shape=box];
+			edge [color=red];
+			C;
+			A -> C;
+			subgraph cluster_0 { D }
+		}`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		asrt.Empty(g.DefaultNodeAttrs().Shape(), "Later defaults should not become graph defaults")
+		asrt.Empty(g.DefaultEdgeAttrs().Color())
+		asrt.Empty(g.GetNode("A").Attrs().Shape())
+		asrt.Empty(g.GetNode("B").Attrs().Shape())
+		asrt.Equal(ShapeBox, g.GetNode("C").Attrs().Shape())
+		asrt.Equal(ShapeBox, g.GetNode("D").Attrs().Shape(), "Subgraphs should inherit the root scope")
+		asrt.Empty(g.Edges()[0].Attrs().Color())
+		asrt.Equal("red", g.Edges()[1].Attrs().Color())
+		asrt.NotContains(g.String(), `node [shape="box"]`)
+	})
+}
+
+func TestParse_SubgraphScopedAttributes(t *testing.T) {
+	t.Run("defaults are recorded on the subgraph", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph {
+			subgraph cluster_0 {
+				node [shape=box];
+				node [color=red];
+				edge [style=dashed];
+				subgraph cluster_1 { node [shape=circle]; C }
+				A -> B;
+			}
+		}`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		outer := g.Subgraphs()[0]
+		asrt.Equal(ShapeBox, outer.DefaultNodeAttrs().Shape())
+		asrt.Equal("red", outer.DefaultNodeAttrs().Color(), "Later default statements should add to earlier ones")
+		asrt.Equal(EdgeStyleDashed, outer.DefaultEdgeAttrs().Style())
+
+		inner := outer.Subgraphs()[0]
+		asrt.Equal(ShapeCircle, inner.DefaultNodeAttrs().Shape())
+		asrt.Empty(inner.DefaultNodeAttrs().Color(), "Nested subgraphs should only record their own defaults")
+		asrt.Empty(g.GetNode("C").Attrs().Color(), "Nested nodes should inherit enclosing defaults when written")
+	})
+
+	t.Run("graph attributes apply to the subgraph", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph {
+			subgraph cluster_0 {
+				label="Cluster";
+				graph [style=filled, fillcolor=lightgray, margin=8];
+				color=blue;
+				A;
+			}
+		}`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		sg := g.Subgraphs()[0]
+		asrt.Equal("Cluster", sg.Attrs().Label())
+		asrt.Equal("filled", sg.Attrs().Style())
+		asrt.Equal("lightgray", sg.Attrs().FillColor())
+		asrt.Equal("blue", sg.Attrs().Color())
+		asrt.Equal("8", sg.Attrs().Custom()["margin"])
+		asrt.Empty(g.Attrs().Custom(), "Subgraph attributes should not leak to the graph")
+		asrt.Equal([]string{"A"}, nodeIDs(g.Nodes()), "Attribute statements should not create nodes")
+	})
+
+	t.Run("attribute statements at the root apply to the graph", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		parser := newParser(`digraph { rankdir=LR; A }`)
+		g, err := parser.parseGraph()
+
+		asrt.NoError(err)
+		asrt.Equal("LR", g.Attrs().Custom()["rankdir"])
+		asrt.Equal([]string{"A"}, nodeIDs(g.Nodes()))
+	})
+}
@@ -0,0 +1,264 @@
+// ABOUTME: Implements weighted shortest path queries over Graph using Dijkstra's algorithm.
+// ABOUTME: Paths can be restyled in place so the result renders directly.
+package goraffe
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// ErrNoPath is returned when the target node cannot be reached from the source node.
+var ErrNoPath = errors.New("no path between nodes")
+
+// ErrNegativeCost is returned when an edge has a negative cost, which Dijkstra's
+// algorithm cannot handle.
+var ErrNegativeCost = errors.New("edge cost cannot be negative")
+
+// Path is a walk through a graph found by ShortestPath or AllPairsShortestPaths.
+type Path struct {
+	// Nodes lists the nodes visited, from source to target.
+	Nodes []*Node
+	// Edges lists the edges taken; Edges[i] joins Nodes[i] and Nodes[i+1].
+	Edges []*Edge
+	// Cost is the sum of the costs of Edges.
+	Cost float64
+}
+
+// Highlight restyles the nodes and edges of the path in place by setting their color
+// and penwidth, so the graph containing them can be rendered with the path emphasized.
+//
+// Example:
+//
+//	p, _ := goraffe.ShortestPath(g, "A", "D")
+//	p.Highlight("red", 2.5)
+//	g.RenderToFile(goraffe.SVG, "path.svg")
+func (p *Path) Highlight(color string, penWidth float64) {
+	width := strconv.FormatFloat(penWidth, 'g', -1, 64)
+
+	for _, n := range p.Nodes {
+		WithColor(color).applyNode(n.attrs)
+		n.attrs.setCustom("penwidth", width)
+	}
+
+	for _, e := range p.Edges {
+		WithEdgeColor(color).applyEdge(e.attrs)
+		e.attrs.setCustom("penwidth", width)
+	}
+}
+
+// PathOption configures shortest path queries.
+type PathOption interface {
+	applyPath(*pathConfig)
+}
+
+// pathConfig holds configuration for shortest path queries.
+type pathConfig struct {
+	cost func(*Edge) float64
+}
+
+type pathOptionFunc func(*pathConfig)
+
+func (f pathOptionFunc) applyPath(cfg *pathConfig) {
+	f(cfg)
+}
+
+// WithCostFunc sets the function used to compute the cost of traversing an edge.
+// By default the cost is the edge's weight (see WithWeight), or 1 if no weight is set.
+//
+// Example:
+//
+//	p, err := goraffe.ShortestPath(g, "A", "D", goraffe.WithCostFunc(func(e *goraffe.Edge) float64 {
+//	    return latency[e.From().ID()+e.To().ID()]
+//	}))
+func WithCostFunc(fn func(*Edge) float64) PathOption {
+	return pathOptionFunc(func(cfg *pathConfig) {
+		cfg.cost = fn
+	})
+}
+
+// weightCost returns the edge's weight attribute, defaulting to 1 when unset
+// as Graphviz does.
+func weightCost(e *Edge) float64 {
+	if e.attrs.weight == nil {
+		return 1
+	}
+
+	return e.attrs.Weight()
+}
+
+func newPathConfig(opts []PathOption) *pathConfig {
+	config := &pathConfig{cost: weightCost}
+	for _, opt := range opts {
+		opt.applyPath(config)
+	}
+
+	return config
+}
+
+// ShortestPath finds the cheapest path from the node with ID from to the node with ID to
+// using Dijkstra's algorithm. Directed graphs follow edge direction; undirected graphs
+// may traverse edges either way. Among equally cheap paths, the one discovered first
+// in insertion order is returned.
+// Returns ErrNodeNotFound if either node is missing, ErrNoPath if to is unreachable,
+// and ErrNegativeCost if a reachable edge has a negative cost.
+//
+// Example:
+//
+//	p, err := goraffe.ShortestPath(g, "A", "D")
+//	fmt.Println(p.Cost)
+func ShortestPath(g *Graph, from, to string, opts ...PathOption) (*Path, error) {
+	for _, id := range []string{from, to} {
+		if g.GetNode(id) == nil {
+			return nil, fmt.Errorf("could not find shortest path for %q: %w", id, ErrNodeNotFound)
+		}
+	}
+
+	tree, err := g.shortestPathTree(from, newPathConfig(opts).cost)
+	if err != nil {
+		return nil, err
+	}
+
+	p := tree.pathTo(to)
+	if p == nil {
+		return nil, fmt.Errorf("could not reach %q from %q: %w", to, from, ErrNoPath)
+	}
+
+	return p, nil
+}
+
+// AllPairsShortestPaths computes the shortest path between every ordered pair of nodes,
+// indexed first by source ID and then by target ID. Unreachable pairs are omitted, and
+// every node has a zero-cost path to itself.
+// Returns ErrNegativeCost if any edge has a negative cost.
+func AllPairsShortestPaths(g *Graph, opts ...PathOption) (map[string]map[string]*Path, error) {
+	cost := newPathConfig(opts).cost
+	paths := make(map[string]map[string]*Path, len(g.nodeOrder))
+
+	for _, source := range g.nodeOrder {
+		tree, err := g.shortestPathTree(source.ID(), cost)
+		if err != nil {
+			return nil, err
+		}
+
+		paths[source.ID()] = make(map[string]*Path)
+		for _, target := range g.nodeOrder {
+			if p := tree.pathTo(target.ID()); p != nil {
+				paths[source.ID()][target.ID()] = p
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+// pathTree is the result of a single-source Dijkstra run.
+type pathTree struct {
+	g      *Graph
+	source string
+	dist   map[string]float64
+	via    map[string]*Edge
+}
+
+// pathTo reconstructs the path from the tree's source to the given node,
+// or returns nil if it is unreachable.
+func (t *pathTree) pathTo(id string) *Path {
+	dist, reached := t.dist[id]
+	if !reached {
+		return nil
+	}
+
+	p := &Path{
+		Nodes: []*Node{t.g.GetNode(id)},
+		Edges: make([]*Edge, 0),
+		Cost:  dist,
+	}
+
+	for id != t.source {
+		e := t.via[id]
+		id = e.otherEnd(id)
+		p.Nodes = append(p.Nodes, t.g.GetNode(id))
+		p.Edges = append(p.Edges, e)
+	}
+
+	slices.Reverse(p.Nodes)
+	slices.Reverse(p.Edges)
+
+	return p
+}
+
+// shortestPathTree runs Dijkstra's algorithm from the given source node.
+func (g *Graph) shortestPathTree(source string, cost func(*Edge) float64) (*pathTree, error) {
+	tree := &pathTree{
+		g:      g,
+		source: source,
+		dist:   map[string]float64{source: 0},
+		via:    make(map[string]*Edge),
+	}
+	settled := make(map[string]bool)
+	frontier := &distanceHeap{}
+	heap.Push(frontier, distanceEntry{node: g.nodes[source], dist: 0})
+
+	for frontier.Len() > 0 {
+		entry, _ := heap.Pop(frontier).(distanceEntry)
+		id := g.nodeOrder[entry.node].ID()
+		if settled[id] {
+			continue
+		}
+		settled[id] = true
+
+		for _, e := range g.traversalEdges(id) {
+			c := cost(e)
+			if c < 0 || math.IsNaN(c) {
+				return nil, fmt.Errorf("could not use edge %s: %w", e.ToString(g.directed), ErrNegativeCost)
+			}
+
+			next := e.otherEnd(id)
+			candidate := entry.dist + c
+			if known, ok := tree.dist[next]; ok && known <= candidate {
+				continue
+			}
+
+			tree.dist[next] = candidate
+			tree.via[next] = e
+			heap.Push(frontier, distanceEntry{node: g.nodes[next], dist: candidate})
+		}
+	}
+
+	return tree, nil
+}
+
+// distanceEntry is a tentative distance to the node at the given insertion index.
+type distanceEntry struct {
+	node int
+	dist float64
+}
+
+// distanceHeap is a min-heap of tentative distances, breaking ties by insertion order.
+type distanceHeap []distanceEntry
+
+func (h distanceHeap) Len() int { return len(h) }
+
+func (h distanceHeap) Less(i, j int) bool {
+	if h[i].dist != h[j].dist {
+		return h[i].dist < h[j].dist
+	}
+	return h[i].node < h[j].node
+}
+
+func (h distanceHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *distanceHeap) Push(x any) {
+	entry, _ := x.(distanceEntry)
+	*h = append(*h, entry)
+}
+
+func (h *distanceHeap) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
@@ -0,0 +1,136 @@
+// ABOUTME: Tests for weighted shortest path queries and path highlighting.
+// ABOUTME: Verifies Dijkstra results, cost functions, error cases and in-place restyling.
+package goraffe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// weightedGraph builds A->B (1), B->D (1), A->C (1), C->D (5), A->D (10).
+func weightedGraph(options ...GraphOption) *Graph {
+	g := NewGraph(options...)
+	a, b, c, d := NewNode("A"), NewNode("B"), NewNode("C"), NewNode("D")
+	_, _ = g.AddEdge(a, b, WithWeight(1))
+	_, _ = g.AddEdge(b, d)
+	_, _ = g.AddEdge(a, c, WithWeight(1))
+	_, _ = g.AddEdge(c, d, WithWeight(5))
+	_, _ = g.AddEdge(a, d, WithWeight(10))
+	return g
+}
+
+func TestShortestPath(t *testing.T) {
+	t.Run("uses edge weights with a default of 1", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := weightedGraph(Directed)
+
+		p, err := ShortestPath(g, "A", "D")
+
+		asrt.NoError(err)
+		asrt.Equal([]string{"A", "B", "D"}, nodeIDs(p.Nodes))
+		asrt.Len(p.Edges, 2)
+		asrt.Equal("B", p.Edges[0].To().ID())
+		asrt.Equal(2.0, p.Cost)
+	})
+
+	t.Run("uses caller supplied cost function", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := weightedGraph(Directed)
+		hops := WithCostFunc(func(*Edge) float64 { return 1 })
+
+		p, err := ShortestPath(g, "A", "D", hops)
+
+		asrt.NoError(err)
+		asrt.Equal([]string{"A", "D"}, nodeIDs(p.Nodes), "expected the direct edge when counting hops")
+		asrt.Equal(1.0, p.Cost)
+	})
+
+	t.Run("respects direction in directed graphs", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := weightedGraph(Directed)
+
+		_, err := ShortestPath(g, "D", "A")
+
+		asrt.ErrorIs(err, ErrNoPath, "expected no path against edge direction")
+	})
+
+	t.Run("traverses both ways in undirected graphs", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := weightedGraph(Undirected)
+
+		p, err := ShortestPath(g, "D", "A")
+
+		asrt.NoError(err)
+		asrt.Equal([]string{"D", "B", "A"}, nodeIDs(p.Nodes))
+	})
+
+	t.Run("path to self is empty", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := weightedGraph(Directed)
+
+		p, err := ShortestPath(g, "A", "A")
+
+		asrt.NoError(err)
+		asrt.Equal([]string{"A"}, nodeIDs(p.Nodes))
+		asrt.Empty(p.Edges)
+		asrt.Zero(p.Cost)
+	})
+
+	t.Run("unknown nodes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := weightedGraph(Directed)
+
+		_, err := ShortestPath(g, "A", "Z")
+
+		asrt.ErrorIs(err, ErrNodeNotFound)
+	})
+
+	t.Run("negative costs", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := weightedGraph(Directed)
+		negative := WithCostFunc(func(*Edge) float64 { return -1 })
+
+		_, err := ShortestPath(g, "A", "D", negative)
+
+		asrt.ErrorIs(err, ErrNegativeCost)
+	})
+}
+
+func TestAllPairsShortestPaths(t *testing.T) {
+	asrt := assert.New(t)
+
+	g := weightedGraph(Directed)
+
+	paths, err := AllPairsShortestPaths(g)
+
+	asrt.NoError(err)
+	asrt.Len(paths, 4, "expected an entry per source node")
+	asrt.Equal(2.0, paths["A"]["D"].Cost)
+	asrt.Equal(5.0, paths["C"]["D"].Cost)
+	asrt.Zero(paths["D"]["D"].Cost, "expected zero-cost path to self")
+	asrt.NotContains(paths["D"], "A", "expected unreachable pairs to be omitted")
+}
+
+func TestPath_Highlight(t *testing.T) {
+	asrt := assert.New(t)
+
+	g := weightedGraph(Directed)
+	p, _ := ShortestPath(g, "A", "D")
+
+	p.Highlight("red", 2.5)
+
+	asrt.Equal("red", g.GetNode("B").Attrs().Color(), "expected path nodes to be restyled")
+	asrt.Equal("2.5", g.GetNode("B").Attrs().Custom()["penwidth"])
+	asrt.Equal("", g.GetNode("C").Attrs().Color(), "expected nodes off the path to be untouched")
+	asrt.Equal("red", p.Edges[0].Attrs().Color(), "expected path edges to be restyled")
+	asrt.Equal(1.0, p.Edges[0].Attrs().Weight(), "expected other edge attributes to be kept")
+	asrt.Contains(g.String(), `"A" -> "B" [color="red", penwidth="2.5", weight="1"];`)
+}
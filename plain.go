@@ -0,0 +1,309 @@
+// ABOUTME: Parses Graphviz plain and plain-ext layout output into a typed structure.
+// ABOUTME: Provides RenderPlain, a lightweight alternative to Layout when only coordinates are needed.
+package goraffe
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// PlainLayout is a graph layout in Graphviz's plain or plain-ext format. Unlike
+// LayoutResult, all positions and sizes are in inches, as the format reports them,
+// and elements are identified by name rather than by the graph's own objects.
+type PlainLayout struct {
+	Scale  float64 // Scale factor Graphviz applied to the layout
+	Width  float64 // Width of the layout in inches
+	Height float64 // Height of the layout in inches
+	Nodes  []PlainNode
+	Edges  []PlainEdge
+}
+
+// PlainNode is a node line of plain output.
+type PlainNode struct {
+	Name      string
+	Center    Point
+	Width     float64
+	Height    float64
+	Label     string
+	Style     string
+	Shape     string
+	Color     string
+	FillColor string
+}
+
+// PlainEdge is an edge line of plain output. TailPort and HeadPort are only set by
+// plain-ext output, for edges attached to ports.
+type PlainEdge struct {
+	Tail     string
+	TailPort string
+	Head     string
+	HeadPort string
+	// Points are the B-spline control points of the edge.
+	Points []Point
+	// Label is the edge label, with LabelPos its position, or empty and nil if there is none.
+	Label    string
+	LabelPos *Point
+	Style    string
+	Color    string
+}
+
+// RenderPlain runs Graphviz with the plain-ext format and parses the result. It is a
+// lighter alternative to Layout for callers that only need coordinates and names.
+// Render options and errors are as for RenderContext.
+//
+// Example:
+//
+//	plain, err := g.RenderPlain(ctx)
+//	for _, n := range plain.Nodes {
+//	    fmt.Println(n.Name, n.Center.X, n.Center.Y)
+//	}
+func (g *Graph) RenderPlain(ctx context.Context, opts ...RenderOption) (*PlainLayout, error) {
+	output, err := g.RenderBytesContext(ctx, PlainExt, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePlain(bytes.NewReader(output))
+}
+
+// ParsePlain parses Graphviz output in the plain or plain-ext format, which consists of
+// a graph line, node and edge lines, and a closing stop line.
+// Returns a ParseError identifying the line if the input is not valid plain output.
+//
+// Example:
+//
+//	out, _ := g.RenderBytes(goraffe.Plain)
+//	plain, err := goraffe.ParsePlain(bytes.NewReader(out))
+func ParsePlain(r io.Reader) (*PlainLayout, error) {
+	layout := &PlainLayout{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+
+	line, stopped := 0, false
+	for scanner.Scan() {
+		line++
+		fields, err := plainFields(scanner.Text())
+		if err == nil && len(fields) > 0 {
+			stopped, err = layout.parseLine(fields)
+		}
+		if err != nil {
+			return nil, &ParseError{Message: err.Error(), Line: line, Col: 1, Snippet: scanner.Text()}
+		}
+		if stopped {
+			return layout, nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, &ParseError{Message: fmt.Sprintf("failed to read input: %v", err)}
+	}
+	return nil, &ParseError{Message: "missing stop line"}
+}
+
+// parseLine adds a single line of plain output to the layout. Returns true for the stop line.
+func (l *PlainLayout) parseLine(fields []string) (bool, error) {
+	switch fields[0] {
+	case "graph":
+		values, err := plainFloats(fields, 1, 3)
+		if err != nil {
+			return false, err
+		}
+		l.Scale, l.Width, l.Height = values[0], values[1], values[2]
+	case "node":
+		n, err := parsePlainNode(fields)
+		if err != nil {
+			return false, err
+		}
+		l.Nodes = append(l.Nodes, n)
+	case "edge":
+		e, err := parsePlainEdge(fields)
+		if err != nil {
+			return false, err
+		}
+		l.Edges = append(l.Edges, e)
+	case "stop":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown statement %q", fields[0])
+	}
+
+	return false, nil
+}
+
+// parsePlainNode parses: node name x y width height label style shape color fillcolor.
+func parsePlainNode(fields []string) (PlainNode, error) {
+	if len(fields) != 11 {
+		return PlainNode{}, fmt.Errorf("expected 11 fields in node line, got %d", len(fields))
+	}
+
+	values, err := plainFloats(fields, 2, 4)
+	if err != nil {
+		return PlainNode{}, err
+	}
+
+	return PlainNode{
+		Name:      unquotePlain(fields[1]),
+		Center:    Point{X: values[0], Y: values[1]},
+		Width:     values[2],
+		Height:    values[3],
+		Label:     unquotePlain(fields[6]),
+		Style:     unquotePlain(fields[7]),
+		Shape:     unquotePlain(fields[8]),
+		Color:     unquotePlain(fields[9]),
+		FillColor: unquotePlain(fields[10]),
+	}, nil
+}
+
+// parsePlainEdge parses: edge tail head n x1 y1 ... xn yn [label xl yl] style color.
+func parsePlainEdge(fields []string) (PlainEdge, error) {
+	if len(fields) < 4 {
+		return PlainEdge{}, fmt.Errorf("expected at least 4 fields in edge line, got %d", len(fields))
+	}
+
+	count, err := strconv.Atoi(fields[3])
+	if err != nil || count < 0 {
+		return PlainEdge{}, fmt.Errorf("invalid point count %q", fields[3])
+	}
+
+	rest := len(fields) - 4 - 2*count
+	if rest != 2 && rest != 5 {
+		return PlainEdge{}, fmt.Errorf("expected %d or %d fields in edge line, got %d",
+			4+2*count+2, 4+2*count+5, len(fields))
+	}
+
+	e := PlainEdge{Points: make([]Point, count)}
+	e.Tail, e.TailPort = splitPlainPort(fields[1])
+	e.Head, e.HeadPort = splitPlainPort(fields[2])
+
+	values, err := plainFloats(fields, 4, 2*count)
+	if err != nil {
+		return PlainEdge{}, err
+	}
+	for i := range e.Points {
+		e.Points[i] = Point{X: values[2*i], Y: values[2*i+1]}
+	}
+
+	next := 4 + 2*count
+	if rest == 5 {
+		pos, err := plainFloats(fields, next+1, 2)
+		if err != nil {
+			return PlainEdge{}, err
+		}
+		e.Label, e.LabelPos = unquotePlain(fields[next]), &Point{X: pos[0], Y: pos[1]}
+		next += 3
+	}
+	e.Style, e.Color = unquotePlain(fields[next]), unquotePlain(fields[next+1])
+
+	return e, nil
+}
+
+// plainFloats parses count fields starting at index start as numbers.
+func plainFloats(fields []string, start, count int) ([]float64, error) {
+	if start+count > len(fields) {
+		return nil, fmt.Errorf("expected %d fields in %s line, got %d", start+count, fields[0], len(fields))
+	}
+
+	values := make([]float64, count)
+	for i := range values {
+		value, err := strconv.ParseFloat(fields[start+i], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", fields[start+i])
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// plainFields splits a line of plain output into fields separated by whitespace. Quoted
+// strings and HTML strings are kept whole, with their delimiters, and may be followed by
+// a port as in "node a":p.
+func plainFields(line string) ([]string, error) {
+	var fields []string
+
+	for i := 0; i < len(line); {
+		if line[i] == ' ' || line[i] == '\t' || line[i] == '\r' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(line) && line[i] != ' ' && line[i] != '\t' && line[i] != '\r' {
+			end, err := plainSegmentEnd(line, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+		}
+		fields = append(fields, line[start:i])
+	}
+
+	return fields, nil
+}
+
+// plainSegmentEnd returns the position just past the quoted string, HTML string or
+// single character starting at i.
+func plainSegmentEnd(line string, i int) (int, error) {
+	switch line[i] {
+	case '"':
+		for j := i + 1; j < len(line); j++ {
+			switch line[j] {
+			case '\\':
+				j++
+			case '"':
+				return j + 1, nil
+			}
+		}
+		return 0, errors.New("unterminated string")
+	case '<':
+		depth := 0
+		for j := i; j < len(line); j++ {
+			switch line[j] {
+			case '<':
+				depth++
+			case '>':
+				depth--
+				if depth == 0 {
+					return j + 1, nil
+				}
+			}
+		}
+		return 0, errors.New("unterminated HTML string")
+	default:
+		return i + 1, nil
+	}
+}
+
+// splitPlainPort splits a plain-ext endpoint such as a:p or "node a":"p 1" into its node
+// name and port. The port is empty if there is none.
+func splitPlainPort(field string) (name, port string) {
+	end := 0
+	for end < len(field) && field[end] != ':' {
+		next, err := plainSegmentEnd(field, end)
+		if err != nil {
+			return unquotePlain(field), ""
+		}
+		end = next
+	}
+
+	if end >= len(field) {
+		return unquotePlain(field), ""
+	}
+	return unquotePlain(field[:end]), unquotePlain(field[end+1:])
+}
+
+// unquotePlain removes the quotes Graphviz adds around strings that are not plain
+// identifiers, resolving escaped quotes. HTML strings are returned with their angle brackets.
+func unquotePlain(field string) string {
+	if len(field) < 2 || field[0] != '"' || field[len(field)-1] != '"' {
+		return field
+	}
+
+	return strings.ReplaceAll(field[1:len(field)-1], `\"`, `"`)
+}
@@ -0,0 +1,137 @@
+// ABOUTME: Tests for parsing Graphviz plain and plain-ext output and for RenderPlain.
+// ABOUTME: Covers quoting, ports, edge labels, malformed input and rendering through a fake dot.
+package goraffe
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plainFixture is plain-ext output for a graph with a quoted node name, ports and an edge label.
+const plainFixture = `graph 1 1.75 2.5
+node A 0.875 2.25 0.75 0.5 A solid ellipse black lightgrey
+node "node B" 0.875 1.25 1.2 0.5 "B \"quoted\"" filled box red yellow
+node C 0.875 0.25 0.75 0.5 <<b>C</b>> solid ellipse black lightgrey
+edge A:s "node B":"in 1" 4 0.875 1.99 0.875 1.87 0.875 1.72 0.875 1.6 "calls" 1.1 1.8 solid black
+edge "node B" C 4 0.875 0.99 0.875 0.87 0.875 0.72 0.875 0.6 dashed blue
+stop
+`
+
+func TestParsePlain(t *testing.T) {
+	t.Run("parses graph, node and edge lines", func(t *testing.T) {
+		req := require.New(t)
+
+		layout, err := ParsePlain(strings.NewReader(plainFixture))
+
+		req.NoError(err)
+		req.Equal(1.0, layout.Scale)
+		req.Equal(1.75, layout.Width)
+		req.Equal(2.5, layout.Height)
+
+		req.Len(layout.Nodes, 3)
+		req.Equal(PlainNode{
+			Name: "node B", Center: Point{0.875, 1.25}, Width: 1.2, Height: 0.5,
+			Label: `B "quoted"`, Style: "filled", Shape: "box", Color: "red", FillColor: "yellow",
+		}, layout.Nodes[1])
+		req.Equal("<<b>C</b>>", layout.Nodes[2].Label)
+
+		req.Len(layout.Edges, 2)
+		req.Equal(PlainEdge{
+			Tail: "A", TailPort: "s", Head: "node B", HeadPort: "in 1",
+			Points:   []Point{{0.875, 1.99}, {0.875, 1.87}, {0.875, 1.72}, {0.875, 1.6}},
+			Label:    "calls",
+			LabelPos: &Point{1.1, 1.8},
+			Style:    "solid",
+			Color:    "black",
+		}, layout.Edges[0])
+		req.Equal("node B", layout.Edges[1].Tail)
+		req.Empty(layout.Edges[1].TailPort)
+		req.Nil(layout.Edges[1].LabelPos)
+		req.Equal("dashed", layout.Edges[1].Style)
+	})
+
+	t.Run("stops at the stop line", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		layout, err := ParsePlain(strings.NewReader("graph 1 1 1\nstop\nnot plain output\n"))
+
+		asrt.NoError(err)
+		asrt.Empty(layout.Nodes)
+	})
+
+	t.Run("reports malformed lines", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			input   string
+			line    int
+			message string
+		}{
+			{"unknown statement", "graph 1 1 1\ncluster x\nstop\n", 2, `unknown statement "cluster"`},
+			{"short node line", "node A 1 2\nstop\n", 1, "expected 11 fields"},
+			{"bad number", "graph 1 wide 1\nstop\n", 1, `invalid number "wide"`},
+			{"wrong point count", "edge A B 2 1 1 2 2 3 3 solid black\nstop\n", 1, "expected 10 or 13 fields"},
+			{"unterminated string", "node \"A 1 1 1 1\nstop\n", 1, "unterminated string"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				asrt := assert.New(t)
+
+				_, err := ParsePlain(strings.NewReader(tt.input))
+
+				var parseErr *ParseError
+				asrt.True(errors.As(err, &parseErr))
+				asrt.Equal(tt.line, parseErr.Line)
+				asrt.Contains(parseErr.Message, tt.message)
+			})
+		}
+	})
+
+	t.Run("requires a stop line", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		_, err := ParsePlain(strings.NewReader("graph 1 1 1\n"))
+
+		asrt.ErrorContains(err, "missing stop line")
+	})
+}
+
+func TestGraph_RenderPlain(t *testing.T) {
+	t.Run("renders plain-ext and parses it", func(t *testing.T) {
+		req := require.New(t)
+		fakeGraphviz(t, `case "$1" in
+  "-T?") echo 'Format: "?" not recognized. Use one of: dot plain plain-ext' >&2; exit 1 ;;
+  -Tplain-ext) ;;
+  *) exit 1 ;;
+esac
+cat <<'EOF'
+`+plainFixture+`EOF`)
+
+		layout, err := NewGraph().RenderPlain(context.Background())
+
+		req.NoError(err)
+		req.Len(layout.Nodes, 3)
+		req.Len(layout.Edges, 2)
+	})
+
+	t.Run("renders with a real Graphviz", func(t *testing.T) {
+		requireGraphviz(t)
+		req := require.New(t)
+
+		g := NewGraph(Directed)
+		_, _ = g.AddEdge(NewNode("A"), NewNode("node B"), WithEdgeLabel("calls"))
+
+		layout, err := g.RenderPlain(context.Background())
+
+		req.NoError(err)
+		req.Len(layout.Nodes, 2)
+		req.Len(layout.Edges, 1)
+		req.Equal("node B", layout.Edges[0].Head)
+		req.Equal("calls", layout.Edges[0].Label)
+	})
+}
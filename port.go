@@ -2,11 +2,42 @@
 // ABOUTME: Ports allow edges to connect to specific cells within HTML table labels.
 package goraffe
 
+// CompassPoint represents the side or corner of a node or port that an edge attaches to.
+// See https://www.graphviz.org/docs/attr-types/portPos/ for details.
+type CompassPoint string
+
+// Compass points supported by Graphviz.
+const (
+	CompassN    CompassPoint = "n"  // Top
+	CompassNE   CompassPoint = "ne" // Top right
+	CompassE    CompassPoint = "e"  // Right
+	CompassSE   CompassPoint = "se" // Bottom right
+	CompassS    CompassPoint = "s"  // Bottom
+	CompassSW   CompassPoint = "sw" // Bottom left
+	CompassW    CompassPoint = "w"  // Left
+	CompassNW   CompassPoint = "nw" // Top left
+	CompassC    CompassPoint = "c"  // Center
+	CompassAuto CompassPoint = "_"  // Whichever side is appropriate
+)
+
+// isCompassPoint reports whether s names one of the compass points supported by Graphviz.
+func isCompassPoint(s string) bool {
+	switch CompassPoint(s) {
+	case CompassN, CompassNE, CompassE, CompassSE, CompassS, CompassSW, CompassW, CompassNW, CompassC, CompassAuto:
+		return true
+	default:
+		return false
+	}
+}
+
 // Port represents a type-safe reference to a port within an HTML label cell.
 // Ports are used to specify connection points for edges within HTML table labels.
+// A port may also carry a compass point, or consist of a compass point alone
+// to pin an edge to one side of a node without a label.
 type Port struct {
-	id     string
-	nodeID string
+	id      string
+	nodeID  string
+	compass CompassPoint
 }
 
 // ID returns the port's identifier string.
@@ -21,6 +52,25 @@ func (p *Port) NodeID() string {
 	return p.nodeID
 }
 
+// Compass returns the port's compass point, or an empty string if none is set.
+func (p *Port) Compass() CompassPoint {
+	return p.compass
+}
+
+// WithCompass returns a copy of the port that attaches edges at the given compass point.
+// The original port is left unchanged, so a single label port can be used with
+// different compass points by different edges.
+//
+// Example:
+//
+//	out := Cell(Text("output")).Port("out").GetPort()
+//	e := g.AddEdge(n1, n2, FromPort(out.WithCompass(CompassSE)))
+func (p *Port) WithCompass(c CompassPoint) *Port {
+	copied := *p
+	copied.compass = c
+	return &copied
+}
+
 // setNodeContext sets the node context for this port.
 // This is used internally when wiring ports to nodes.
 func (p *Port) setNodeContext(node *Node) {
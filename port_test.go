@@ -37,3 +37,18 @@ func TestCell_GetPort_NilIfNoPort(t *testing.T) {
 	port := cell.GetPort()
 	asrt.Nil(port, "expected GetPort() to return nil when no port is set")
 }
+
+func TestPort_WithCompass(t *testing.T) {
+	t.Run("returns a copy with the compass point", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		port := &Port{id: "out", nodeID: "A"}
+		withCompass := port.WithCompass(CompassSE)
+
+		asrt.NotSame(port, withCompass, "expected WithCompass to return a new Port")
+		asrt.Equal(CompassSE, withCompass.Compass())
+		asrt.Equal("out", withCompass.ID())
+		asrt.Equal("A", withCompass.NodeID())
+		asrt.Empty(port.Compass(), "expected original port to be unchanged")
+	})
+}
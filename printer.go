@@ -0,0 +1,457 @@
+// ABOUTME: Provides DOTPrinter for writing graphs as DOT source in a configurable style.
+// ABOUTME: Controls indentation, ID quoting, attribute order and layout, and element comments.
+package goraffe
+
+import (
+	"cmp"
+	"io"
+	"slices"
+	"strings"
+)
+
+// AttributeOrder controls the order in which a DOTPrinter writes the attributes of a statement.
+type AttributeOrder int
+
+const (
+	// AttributeOrderDefault writes attributes in the order Graph.String always has:
+	// sorted by name, except that subgraph attributes are written in declared order.
+	// This is the default.
+	AttributeOrderDefault AttributeOrder = iota
+	// AttributeOrderSorted writes the attributes of every statement sorted by name.
+	AttributeOrderSorted
+	// AttributeOrderDeclared writes typed attributes in the order their attribute type
+	// declares them, followed by custom attributes sorted by name.
+	AttributeOrderDeclared
+)
+
+// dotKeywords are the DOT keywords, which must be quoted when used as IDs.
+var dotKeywords = []string{"node", "edge", "graph", "digraph", "subgraph", "strict"}
+
+// defaultPrinter produces the output of Graph.String and the String methods of graph elements.
+var defaultPrinter = NewDOTPrinter()
+
+// DOTPrinter writes graphs as DOT source. Create printers with NewDOTPrinter;
+// a printer created without options produces exactly the output of Graph.String.
+// A DOTPrinter is not modified by printing and may be reused across graphs.
+type DOTPrinter struct {
+	indent        string
+	minimalQuotes bool
+	attrPerLine   bool
+	compact       bool
+	blockComments bool
+	attrOrder     AttributeOrder
+	nodeComment   func(*Node) string
+	edgeComment   func(*Edge) string
+}
+
+// PrinterOption configures a DOTPrinter.
+type PrinterOption interface {
+	applyPrinter(*DOTPrinter)
+}
+
+type printerOptionFunc func(*DOTPrinter)
+
+func (f printerOptionFunc) applyPrinter(p *DOTPrinter) {
+	f(p)
+}
+
+// WithIndent sets the string used for each level of indentation. Default is a tab.
+func WithIndent(indent string) PrinterOption {
+	return printerOptionFunc(func(p *DOTPrinter) {
+		p.indent = indent
+	})
+}
+
+// WithMinimalQuoting quotes IDs and attribute values only when the DOT grammar requires it.
+// Plain identifiers such as A or node_1 and numerals such as -1.5 are written bare;
+// keywords, empty strings and anything containing other characters are still quoted.
+func WithMinimalQuoting() PrinterOption {
+	return printerOptionFunc(func(p *DOTPrinter) {
+		p.minimalQuotes = true
+	})
+}
+
+// WithAttributePerLine writes each attribute of a node, edge or default statement on
+// its own line instead of keeping the whole attribute list on one line.
+// It has no effect in compact mode.
+func WithAttributePerLine() PrinterOption {
+	return printerOptionFunc(func(p *DOTPrinter) {
+		p.attrPerLine = true
+	})
+}
+
+// WithCompact writes the whole graph on a single line, with statements separated by spaces.
+// Comments are written in /* */ form so they do not end the line.
+func WithCompact() PrinterOption {
+	return printerOptionFunc(func(p *DOTPrinter) {
+		p.compact = true
+	})
+}
+
+// WithBlockComments writes comments as /* */ blocks instead of // lines.
+func WithBlockComments() PrinterOption {
+	return printerOptionFunc(func(p *DOTPrinter) {
+		p.blockComments = true
+	})
+}
+
+// WithAttributeOrder sets the order in which the attributes of each statement are written.
+// Default is AttributeOrderDefault.
+func WithAttributeOrder(order AttributeOrder) PrinterOption {
+	return printerOptionFunc(func(p *DOTPrinter) {
+		p.attrOrder = order
+	})
+}
+
+// WithNodeComments writes the text returned by fn as a comment before each node
+// statement, after the node's own comment if it has one. Nodes for which fn returns
+// an empty string get no extra comment.
+//
+// Example:
+//
+//	p := NewDOTPrinter(WithNodeComments(func(n *Node) string {
+//		return owners[n.ID()]
+//	}))
+func WithNodeComments(fn func(*Node) string) PrinterOption {
+	return printerOptionFunc(func(p *DOTPrinter) {
+		p.nodeComment = fn
+	})
+}
+
+// WithEdgeComments writes the text returned by fn as a comment before each edge
+// statement, after the edge's own comment if it has one. Edges for which fn returns
+// an empty string get no extra comment.
+func WithEdgeComments(fn func(*Edge) string) PrinterOption {
+	return printerOptionFunc(func(p *DOTPrinter) {
+		p.edgeComment = fn
+	})
+}
+
+// NewDOTPrinter creates a printer configured by the given options.
+//
+// Example:
+//
+//	p := NewDOTPrinter(WithIndent("  "), WithMinimalQuoting(), WithAttributePerLine())
+//	fmt.Println(p.Print(g))
+func NewDOTPrinter(opts ...PrinterOption) *DOTPrinter {
+	p := &DOTPrinter{indent: "\t"}
+	for _, opt := range opts {
+		opt.applyPrinter(p)
+	}
+
+	return p
+}
+
+// Print returns the DOT source for the graph.
+func (p *DOTPrinter) Print(g *Graph) string {
+	builder := strings.Builder{}
+	p.writeGraph(&builder, g)
+
+	return builder.String()
+}
+
+// Fprint writes the DOT source for the graph to w.
+func (p *DOTPrinter) Fprint(w io.Writer, g *Graph) error {
+	_, err := io.WriteString(w, p.Print(g))
+
+	return err
+}
+
+// writeGraph writes the graph in the order documented on Graph.String.
+func (p *DOTPrinter) writeGraph(builder *strings.Builder, g *Graph) {
+	header := "graph"
+	if g.directed {
+		header = "digraph"
+	}
+	if g.strict {
+		header = "strict " + header
+	}
+	if g.name != "" {
+		header += " " + p.id(g.name)
+	}
+	p.comment(builder, 0, g.comment)
+	p.line(builder, 0, header+" {")
+
+	for _, attr := range p.ordered(g.attrs.attributes()) {
+		p.line(builder, 1, p.attr(attr)+";")
+	}
+	p.writeDefaults(builder, 1, g.defaultNodeAttrs, g.defaultEdgeAttrs)
+
+	// Output subgraphs with their nodes and edges
+	owners := edgeOwners(g.subgraphs)
+	for _, subgraph := range g.subgraphs {
+		p.writeSubgraph(builder, subgraph, 1, owners)
+	}
+
+	// Output nodes not in any subgraph
+	nodesInSubgraphs := g.collectNodesInSubgraphs()
+	for _, node := range g.nodeOrder {
+		if !nodesInSubgraphs[node.ID()] {
+			p.writeNode(builder, node, 1)
+		}
+	}
+
+	// Output edges not owned by any subgraph
+	for edge := range g.edges.all() {
+		if owners[edge] == nil {
+			p.writeEdge(builder, edge, g.directed, 1)
+		}
+	}
+
+	builder.WriteString("}")
+}
+
+// writeSubgraph writes the subgraph and its nested subgraphs at the given depth.
+// Edges are written only in the block of the subgraph that owns them.
+func (p *DOTPrinter) writeSubgraph(builder *strings.Builder, sg *Subgraph, depth int, owners map[*Edge]*Subgraph) {
+	p.comment(builder, depth, sg.comment)
+
+	// Anonymous subgraphs (empty name) don't include a name
+	if sg.name == "" {
+		p.line(builder, depth, "subgraph {")
+	} else {
+		p.line(builder, depth, "subgraph "+p.id(sg.name)+" {")
+	}
+
+	if sg.attrs != nil {
+		for _, attr := range p.orderedSubgraphAttrs(sg.attrs.attributes()) {
+			p.line(builder, depth+1, p.attr(attr)+";")
+		}
+	}
+	p.writeDefaults(builder, depth+1, sg.defaultNodeAttrs, sg.defaultEdgeAttrs)
+
+	// Recursively render nested subgraphs first
+	for _, nested := range sg.subgraphs {
+		p.writeSubgraph(builder, nested, depth+1, owners)
+	}
+
+	// Add nodes that belong directly to this subgraph (not in nested subgraphs)
+	nodesInNested := make(map[string]bool)
+	for _, nested := range sg.subgraphs {
+		for id := range nested.nodes {
+			nodesInNested[id] = true
+		}
+	}
+
+	for _, node := range sg.nodeOrder {
+		if !nodesInNested[node.ID()] {
+			p.writeNode(builder, node, depth+1)
+		}
+	}
+
+	// Add edges owned by this subgraph
+	for edge := range sg.edges.all() {
+		if owners[edge] == sg {
+			p.writeEdge(builder, edge, sg.parent.directed, depth+1)
+		}
+	}
+
+	p.line(builder, depth, "}")
+}
+
+// writeDefaults writes node [...] and edge [...] statements for the given default
+// attributes. Statements without attributes are omitted.
+func (p *DOTPrinter) writeDefaults(builder *strings.Builder, depth int, node *NodeAttributes, edge *EdgeAttributes) {
+	if node != nil {
+		if attrs := node.attributes(); len(attrs) > 0 {
+			p.line(builder, depth, "node "+p.attrList(attrs, depth)+";")
+		}
+	}
+	if edge != nil {
+		if attrs := edge.attributes(); len(attrs) > 0 {
+			p.line(builder, depth, "edge "+p.attrList(attrs, depth)+";")
+		}
+	}
+}
+
+// writeNode writes a node statement, preceded by its comments if any.
+func (p *DOTPrinter) writeNode(builder *strings.Builder, n *Node, depth int) {
+	p.comment(builder, depth, n.comment)
+	if p.nodeComment != nil {
+		p.comment(builder, depth, p.nodeComment(n))
+	}
+	p.line(builder, depth, p.node(n, depth)+";")
+}
+
+// writeEdge writes an edge statement, preceded by its comments if any.
+func (p *DOTPrinter) writeEdge(builder *strings.Builder, e *Edge, directed bool, depth int) {
+	p.comment(builder, depth, e.comment)
+	if p.edgeComment != nil {
+		p.comment(builder, depth, p.edgeComment(e))
+	}
+	p.line(builder, depth, p.edge(e, directed, depth)+";")
+}
+
+// node returns a node statement without its terminating semicolon.
+func (p *DOTPrinter) node(n *Node, depth int) string {
+	stmt := p.id(n.ID())
+	if attrs := n.attrs.attributes(); len(attrs) > 0 {
+		stmt += " " + p.attrList(attrs, depth)
+	}
+
+	return stmt
+}
+
+// edge returns an edge statement without its terminating semicolon.
+func (p *DOTPrinter) edge(e *Edge, directed bool, depth int) string {
+	op := " -- "
+	if directed {
+		op = " -> "
+	}
+
+	stmt := p.endpoint(e.from.ID(), e.attrs.fromPort) + op + p.endpoint(e.to.ID(), e.attrs.toPort)
+	if attrs := e.attrs.attributes(); len(attrs) > 0 {
+		stmt += " " + p.attrList(attrs, depth)
+	}
+
+	return stmt
+}
+
+// endpoint returns a node ID followed by the port and compass point, if any,
+// in DOT's node_id : port : compass_pt form.
+func (p *DOTPrinter) endpoint(nodeID string, port *Port) string {
+	endpoint := p.id(nodeID)
+	if port == nil {
+		return endpoint
+	}
+
+	if port.id != "" {
+		endpoint += ":" + p.id(port.id)
+	}
+	if port.compass != "" {
+		endpoint += ":" + string(port.compass)
+	}
+
+	return endpoint
+}
+
+// attrList returns a bracketed attribute list for a statement at the given depth.
+func (p *DOTPrinter) attrList(attrs []attribute, depth int) string {
+	formatted := make([]string, 0, len(attrs))
+	for _, attr := range p.ordered(attrs) {
+		formatted = append(formatted, p.attr(attr))
+	}
+
+	if !p.attrPerLine || p.compact {
+		return "[" + strings.Join(formatted, ", ") + "]"
+	}
+
+	inner := "\n" + strings.Repeat(p.indent, depth+1)
+	return "[" + inner + strings.Join(formatted, ","+inner) + "\n" + strings.Repeat(p.indent, depth) + "]"
+}
+
+// attr returns a single attribute in name=value form.
+func (p *DOTPrinter) attr(attr attribute) string {
+	if !attr.html && p.minimalQuotes && isBareDOTID(attr.value) {
+		return attr.name + "=" + attr.value
+	}
+
+	return attr.String()
+}
+
+// ordered returns attrs in the printer's attribute order.
+func (p *DOTPrinter) ordered(attrs []attribute) []attribute {
+	if p.attrOrder == AttributeOrderDeclared {
+		return attrs
+	}
+
+	return slices.SortedStableFunc(slices.Values(attrs), func(a, b attribute) int {
+		return cmp.Compare(a.name, b.name)
+	})
+}
+
+// orderedSubgraphAttrs returns a subgraph's attributes in the printer's attribute order.
+// Only AttributeOrderSorted sorts them; by default they keep their declared order.
+func (p *DOTPrinter) orderedSubgraphAttrs(attrs []attribute) []attribute {
+	if p.attrOrder != AttributeOrderSorted {
+		return attrs
+	}
+
+	return p.ordered(attrs)
+}
+
+// id returns s as a DOT ID, quoting it unless minimal quoting is enabled and s
+// is a plain identifier or numeral.
+func (p *DOTPrinter) id(s string) string {
+	if p.minimalQuotes && isBareDOTID(s) {
+		return s
+	}
+
+	return quoteDOTID(s)
+}
+
+// comment writes text as a comment at the given depth, as one // line per line of
+// text or as a /* */ block. In compact mode the text is written as a single-line block.
+func (p *DOTPrinter) comment(builder *strings.Builder, depth int, text string) {
+	if text == "" {
+		return
+	}
+
+	switch {
+	case p.compact:
+		p.line(builder, depth, "/* "+blockCommentText(strings.ReplaceAll(text, "\n", " "))+" */")
+	case p.blockComments && !strings.Contains(text, "\n"):
+		p.line(builder, depth, "/* "+blockCommentText(text)+" */")
+	case p.blockComments:
+		p.line(builder, depth, "/*")
+		for line := range strings.SplitSeq(blockCommentText(text), "\n") {
+			p.line(builder, depth, strings.TrimRight(" * "+line, " "))
+		}
+		p.line(builder, depth, " */")
+	default:
+		for line := range strings.SplitSeq(text, "\n") {
+			p.line(builder, depth, strings.TrimRight("// "+line, " "))
+		}
+	}
+}
+
+// blockCommentText breaks up any */ in text so it cannot end a /* */ comment early.
+func blockCommentText(text string) string {
+	return strings.ReplaceAll(text, "*/", "* /")
+}
+
+// line writes one line of output at the given depth. In compact mode the line is
+// followed by a space instead of a newline, and indentation is omitted.
+func (p *DOTPrinter) line(builder *strings.Builder, depth int, s string) {
+	if p.compact {
+		builder.WriteString(s)
+		builder.WriteString(" ")
+		return
+	}
+
+	builder.WriteString(strings.Repeat(p.indent, depth))
+	builder.WriteString(s)
+	builder.WriteString("\n")
+}
+
+// isBareDOTID reports whether s can be written as a DOT ID without quotes: an
+// identifier made of letters, digits and underscores that does not start with a
+// digit and is not a keyword, or a numeral such as 42, -3 or 1.5.
+func isBareDOTID(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	if isIdentStart(s[0]) {
+		for i := 1; i < len(s); i++ {
+			if !isIdentChar(s[i]) {
+				return false
+			}
+		}
+		return !slices.Contains(dotKeywords, strings.ToLower(s))
+	}
+
+	return isDOTNumeral(s)
+}
+
+// isDOTNumeral reports whether s matches DOT's numeral syntax: [-]?(.[0-9]+ | [0-9]+(.[0-9]*)?).
+func isDOTNumeral(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	whole, frac, hasDot := strings.Cut(s, ".")
+
+	if whole+frac == "" || (!hasDot && whole == "") {
+		return false
+	}
+
+	return strings.Trim(whole, "0123456789") == "" && strings.Trim(frac, "0123456789") == ""
+}
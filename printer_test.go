@@ -0,0 +1,233 @@
+// ABOUTME: Tests for the configurable DOT printer.
+// ABOUTME: Verifies indentation, quoting, attribute layout and order, comments and WriteDOT options.
+package goraffe
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// printerTestGraph builds a small graph exercising subgraphs, defaults and attributes.
+func printerTestGraph() *Graph {
+	g := NewGraph(Directed, WithName("G"), WithDefaultNodeAttrs(WithBoxShape(), WithColor("gray")))
+	a := NewNode("A", WithLabel("Start"), WithColor("red"))
+	b := NewNode("node B")
+	g.Subgraph("cluster_0", func(s *Subgraph) {
+		s.SetLabel("group")
+		_ = s.AddNode(a)
+	})
+	_, _ = g.AddEdge(a, b, WithEdgeColor("blue"), WithWeight(2))
+
+	return g
+}
+
+func TestDOTPrinter_Default(t *testing.T) {
+	t.Run("matches Graph.String", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := printerTestGraph()
+
+		asrt.Equal(g.String(), NewDOTPrinter().Print(g))
+	})
+}
+
+func TestDOTPrinter_WithIndent(t *testing.T) {
+	t.Run("indents each level with the given string", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		output := NewDOTPrinter(WithIndent("  ")).Print(printerTestGraph())
+
+		asrt.Contains(output, "\n  subgraph \"cluster_0\" {\n    label=\"group\";\n")
+		asrt.Contains(output, "\n  }\n")
+		asrt.NotContains(output, "\t")
+	})
+}
+
+func TestDOTPrinter_WithMinimalQuoting(t *testing.T) {
+	t.Run("writes plain identifiers and numerals bare", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		output := NewDOTPrinter(WithMinimalQuoting()).Print(printerTestGraph())
+
+		asrt.Contains(output, "digraph G {\n")
+		asrt.Contains(output, "\tnode [color=gray, shape=box];\n")
+		asrt.Contains(output, "\t\tA [color=red, label=Start];\n")
+		asrt.Contains(output, "\tA -> \"node B\" [color=blue, weight=2];\n")
+	})
+
+	t.Run("quotes keywords, empty strings and other characters", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		_, _ = g.AddEdge(NewNode("Node"), NewNode("1a"), WithEdgeLabel(""))
+		_ = g.AddNode(NewNode("a-b"))
+
+		output := NewDOTPrinter(WithMinimalQuoting()).Print(g)
+
+		asrt.Contains(output, "\t\"a-b\";\n")
+		asrt.Contains(output, "\t\"Node\" -> \"1a\" [label=\"\"];\n")
+	})
+
+	t.Run("keeps HTML labels and compass points unquoted", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		n, u := NewNode("T", WithHTMLLabel(HTMLTable(Row(Cell(Text("x")).Port("p"))))), NewNode("U")
+		_, _ = g.AddEdge(n, u, FromPort(n.Attrs().HTMLLabel().GetPort("p")), ToCompass(u, CompassN))
+
+		output := NewDOTPrinter(WithMinimalQuoting()).Print(g)
+
+		asrt.Contains(output, "\tT [label=<<table>")
+		asrt.Contains(output, "\tT:p -> U:n;\n")
+	})
+
+	t.Run("recognizes DOT identifiers and numerals", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		for _, bare := range []string{"A", "_x1", "node_1", "42", "-3", "1.5", ".5", "5.", "-.5"} {
+			asrt.True(isBareDOTID(bare), "expected %q to be bare", bare)
+		}
+		for _, quoted := range []string{"", "1a", "a b", "a-b", "-", ".", "1.2.3", "graph", "Subgraph", "é"} {
+			asrt.False(isBareDOTID(quoted), "expected %q to be quoted", quoted)
+		}
+	})
+}
+
+func TestDOTPrinter_WithAttributePerLine(t *testing.T) {
+	t.Run("writes each attribute on its own line", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		output := NewDOTPrinter(WithAttributePerLine()).Print(printerTestGraph())
+
+		asrt.Contains(output, "\tnode [\n\t\tcolor=\"gray\",\n\t\tshape=\"box\"\n\t];\n")
+		asrt.Contains(output, "\t\t\"A\" [\n\t\t\tcolor=\"red\",\n\t\t\tlabel=\"Start\"\n\t\t];\n")
+		asrt.Contains(output, "\t\"A\" -> \"node B\" [\n\t\tcolor=\"blue\",\n\t\tweight=\"2\"\n\t];\n")
+	})
+}
+
+func TestDOTPrinter_WithCompact(t *testing.T) {
+	t.Run("writes the graph on a single line", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		_, _ = g.AddEdge(NewNode("A"), NewNode("B"), WithEdgeColor("red"))
+
+		output := NewDOTPrinter(WithCompact(), WithAttributePerLine()).Print(g)
+
+		asrt.Equal(`digraph { "A"; "B"; "A" -> "B" [color="red"]; }`, output)
+	})
+
+	t.Run("output can be parsed back", func(t *testing.T) {
+		req := require.New(t)
+
+		g := printerTestGraph()
+		output := NewDOTPrinter(WithCompact()).Print(g)
+		req.NotContains(output, "\n")
+
+		parsed, err := ParseString(output)
+		req.NoError(err)
+		req.Equal(g.String(), parsed.String())
+	})
+}
+
+func TestDOTPrinter_WithAttributeOrder(t *testing.T) {
+	t.Run("sorts attributes by default, except subgraph attributes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := printerTestGraph()
+		g.Subgraph("cluster_0", func(s *Subgraph) {
+			s.SetStyle("filled")
+			s.SetLabel("group")
+		})
+
+		output := NewDOTPrinter().Print(g)
+
+		asrt.Contains(output, `"A" [color="red", label="Start"]`)
+		asrt.Contains(output, "\t\tlabel=\"group\";\n\t\tstyle=\"filled\";\n")
+		asrt.Equal(g.String(), output)
+	})
+
+	t.Run("sorted order also sorts subgraph attributes", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		g.Subgraph("cluster_0", func(s *Subgraph) {
+			s.SetStyle("filled")
+			s.SetLabel("group")
+			s.SetColor("blue")
+		})
+
+		output := NewDOTPrinter(WithAttributeOrder(AttributeOrderSorted)).Print(g)
+
+		asrt.Contains(output, "\t\tcolor=\"blue\";\n\t\tlabel=\"group\";\n\t\tstyle=\"filled\";\n")
+	})
+
+	t.Run("declared order follows the attribute type", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		g.Subgraph("cluster_0", func(s *Subgraph) {
+			s.SetAttribute("penwidth", "2")
+			s.SetStyle("filled")
+			s.SetLabel("group")
+		})
+		_ = g.AddNode(NewNode("A", WithNodeAttribute("tooltip", "t"), WithColor("red"), WithLabel("Start")))
+
+		output := NewDOTPrinter(WithAttributeOrder(AttributeOrderDeclared)).Print(g)
+
+		asrt.Contains(output, "\t\tlabel=\"group\";\n\t\tstyle=\"filled\";\n\t\tpenwidth=\"2\";\n")
+		asrt.Contains(output, `"A" [label="Start", color="red", tooltip="t"]`)
+	})
+}
+
+func TestDOTPrinter_Comments(t *testing.T) {
+	t.Run("writes node and edge comments before their statements", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		_, _ = g.AddEdge(NewNode("A"), NewNode("B"))
+
+		output := NewDOTPrinter(
+			WithNodeComments(func(n *Node) string {
+				if n.ID() == "A" {
+					return "entry point\nadded by the importer"
+				}
+				return ""
+			}),
+			WithEdgeComments(func(e *Edge) string { return e.From().ID() + " feeds " + e.To().ID() }),
+		).Print(g)
+
+		asrt.Equal("digraph {\n\t// entry point\n\t// added by the importer\n\t\"A\";\n\t\"B\";\n"+
+			"\t// A feeds B\n\t\"A\" -> \"B\";\n}", output)
+	})
+
+	t.Run("uses block comments in compact mode", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		_ = g.AddNode(NewNode("A"))
+
+		output := NewDOTPrinter(WithCompact(), WithNodeComments(func(*Node) string { return "a */ b" })).Print(g)
+
+		asrt.Equal(`graph { /* a * / b */ "A"; }`, output)
+	})
+}
+
+func TestGraph_WriteDOT_WithOptions(t *testing.T) {
+	t.Run("applies printer options", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := printerTestGraph()
+		var buf bytes.Buffer
+
+		err := g.WriteDOT(&buf, WithIndent("    "), WithMinimalQuoting())
+
+		asrt.NoError(err)
+		asrt.Equal(NewDOTPrinter(WithIndent("    "), WithMinimalQuoting()).Print(g), buf.String())
+		asrt.True(strings.HasPrefix(buf.String(), "digraph G {\n    node [color=gray, shape=box];\n"))
+	})
+}
@@ -0,0 +1,97 @@
+// ABOUTME: Builds derived graphs that contain a subset of another graph's nodes and edges.
+// ABOUTME: Derived graphs keep the source's attributes, defaults and pruned subgraph hierarchy.
+package goraffe
+
+// project returns a new graph holding the nodes accepted by keepNode and the edges
+// accepted by keepEdge whose endpoints are both kept. The new graph copies the source's
+// name, type, attributes and defaults, and mirrors its subgraph hierarchy, dropping
+// subgraphs left without nodes. Node and Edge values are shared with the source graph.
+func (g *Graph) project(keepNode func(*Node) bool, keepEdge func(*Edge) bool) *Graph {
+	derived := g.derive()
+
+	for _, n := range g.nodeOrder {
+		if keepNode(n) {
+			_ = derived.AddNode(n) // Safe to ignore - nodes in a graph are never nil
+		}
+	}
+
+	keptEdges := make(map[*Edge]bool)
+	for e := range g.edges.all() {
+		if derived.GetNode(e.from.ID()) == nil || derived.GetNode(e.to.ID()) == nil || !keepEdge(e) {
+			continue
+		}
+
+		// Edges are copied as-is rather than through AddEdge, which could merge them
+		derived.addEdge(e)
+		keptEdges[e] = true
+	}
+
+	for _, sg := range g.subgraphs {
+		if projected := sg.project(derived, keptEdges); projected != nil {
+			derived.subgraphs = append(derived.subgraphs, projected)
+		}
+	}
+
+	return derived
+}
+
+// derive returns an empty graph with g's name, type, comment, attributes and defaults.
+func (g *Graph) derive() *Graph {
+	derived := NewGraph()
+	derived.name = g.name
+	derived.directed = g.directed
+	derived.strict = g.strict
+	derived.comment = g.comment
+	derived.attrs = g.attrs.clone()
+	derived.defaultNodeAttrs = g.defaultNodeAttrs.clone()
+	derived.defaultEdgeAttrs = g.defaultEdgeAttrs.clone()
+
+	return derived
+}
+
+// project mirrors this subgraph into the derived graph, keeping only the nodes present
+// in derived and the edges in keptEdges. Returns nil if the subgraph and all of its
+// nested subgraphs are left without nodes.
+func (sg *Subgraph) project(derived *Graph, keptEdges map[*Edge]bool) *Subgraph {
+	projected := sg.derive(derived)
+
+	for _, n := range sg.nodeOrder {
+		if kept := derived.GetNode(n.ID()); kept != nil {
+			projected.addNode(kept)
+		}
+	}
+
+	for e := range sg.edges.all() {
+		if keptEdges[e] {
+			projected.edges.add(e)
+		}
+	}
+
+	for _, nested := range sg.subgraphs {
+		if p := nested.project(derived, keptEdges); p != nil {
+			projected.subgraphs = append(projected.subgraphs, p)
+		}
+	}
+
+	if len(projected.nodes) == 0 && len(projected.subgraphs) == 0 {
+		return nil
+	}
+
+	return projected
+}
+
+// derive returns an empty subgraph of parent with this subgraph's name, comment,
+// attributes and defaults.
+func (sg *Subgraph) derive(parent *Graph) *Subgraph {
+	derived := &Subgraph{
+		name:      sg.name,
+		nodes:     make(map[string]int),
+		parent:    parent,
+		subgraphs: make([]*Subgraph, 0),
+		comment:   sg.comment,
+	}
+
+	sg.cloneAttrsInto(derived)
+
+	return derived
+}
@@ -0,0 +1,127 @@
+// ABOUTME: Parses DOT record label syntax into RecordLabel structures.
+// ABOUTME: Handles ports, nested field groups and the escapes produced by escapeRecordString.
+package goraffe
+
+import "strings"
+
+// recordSpecialChars are the characters that structure a record label unless escaped.
+const recordSpecialChars = "|{}<>"
+
+// recordParser is a recursive descent parser over record label syntax:
+//
+//	rlabel  = field ( '|' field )*
+//	field   = fieldID | '{' rlabel '}'
+//	fieldID = [ '<' port '>' ] [ text ]
+type recordParser struct {
+	s   string
+	pos int
+}
+
+// parseRecordLabel parses a record label such as "<f0> left|{<f1> mid|<f2> right}".
+// Returns false if the label is not valid record syntax, for example because of
+// unbalanced braces or an unescaped special character inside a field.
+func parseRecordLabel(s string) (*RecordLabel, bool) {
+	p := &recordParser{s: s}
+
+	elements, ok := p.fields()
+	if !ok || p.pos < len(p.s) {
+		return nil, false
+	}
+
+	return Record(elements...), true
+}
+
+// peek reports whether the next unread byte is ch.
+func (p *recordParser) peek(ch byte) bool {
+	return p.pos < len(p.s) && p.s[p.pos] == ch
+}
+
+// skipSpace skips unescaped whitespace.
+func (p *recordParser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// fields parses a '|'-separated list of fields, stopping at the end of input or at a '}'.
+func (p *recordParser) fields() ([]RecordElement, bool) {
+	var elements []RecordElement
+
+	for {
+		elem, ok := p.field()
+		if !ok {
+			return nil, false
+		}
+		elements = append(elements, elem)
+
+		if !p.peek('|') {
+			return elements, true
+		}
+		p.pos++
+	}
+}
+
+// field parses a single field or a braced group of fields.
+func (p *recordParser) field() (RecordElement, bool) {
+	p.skipSpace()
+
+	if p.peek('{') {
+		p.pos++
+		elements, ok := p.fields()
+		if !ok || !p.peek('}') {
+			return nil, false
+		}
+		p.pos++
+		p.skipSpace()
+		return FieldGroup(elements...), true
+	}
+
+	field := Field("")
+	if p.peek('<') {
+		p.pos++
+		port := p.text()
+		if !p.peek('>') {
+			return nil, false
+		}
+		p.pos++
+		field.Port(strings.TrimSpace(port))
+	}
+
+	field.content = strings.TrimSpace(p.text())
+
+	// Only a field separator, the end of a group or the end of input may follow
+	if p.pos < len(p.s) && !p.peek('|') && !p.peek('}') {
+		return nil, false
+	}
+
+	return field, true
+}
+
+// text reads up to the next unescaped special character, resolving the escapes written
+// by escapeRecordString. Label escapes such as \l are kept with their backslash, and
+// escapeRecordString writes them back unchanged.
+func (p *recordParser) text() string {
+	var sb strings.Builder
+
+	for p.pos < len(p.s) {
+		ch := p.s[p.pos]
+		if strings.IndexByte(recordSpecialChars, ch) >= 0 {
+			break
+		}
+
+		if ch == '\\' && p.pos+1 < len(p.s) {
+			next := p.s[p.pos+1]
+			if next != '\\' && strings.IndexByte(recordSpecialChars, next) < 0 {
+				sb.WriteByte(ch)
+			}
+			sb.WriteByte(next)
+			p.pos += 2
+			continue
+		}
+
+		sb.WriteByte(ch)
+		p.pos++
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,94 @@
+// ABOUTME: Tests for parsing DOT record label syntax into RecordLabel structures.
+// ABOUTME: Verifies fields, ports, nested groups, escapes and invalid labels.
+package goraffe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecordLabel(t *testing.T) {
+	t.Run("parses fields, ports and groups", func(t *testing.T) {
+		asrt := assert.New(t)
+		req := require.New(t)
+
+		label, ok := parseRecordLabel("<f0> left|{<f1> mid|<f2> right}")
+		req.True(ok, "expected valid record syntax")
+		req.Len(label.elements, 2)
+
+		left := label.elements[0].(*RecordField)
+		asrt.Equal("left", left.content)
+		asrt.Equal("f0", left.GetPort().ID())
+
+		group := label.elements[1].(*RecordGroup)
+		req.Len(group.elements, 2)
+		asrt.Equal("mid", group.elements[0].(*RecordField).content)
+		asrt.Same(group.elements[1].(*RecordField).GetPort(), label.findPort("f2"))
+
+		asrt.Equal("<f0> left | { <f1> mid | <f2> right }", label.String())
+	})
+
+	t.Run("resolves escaped special characters", func(t *testing.T) {
+		asrt := assert.New(t)
+		req := require.New(t)
+
+		label, ok := parseRecordLabel(`a\|b \{c\} \<d\> e\\f|left\l`)
+		req.True(ok)
+		req.Len(label.elements, 2)
+
+		asrt.Equal(`a|b {c} <d> e\f`, label.elements[0].(*RecordField).content)
+		asrt.Equal(`left\l`, label.elements[1].(*RecordField).content, "expected other escapes to be kept")
+	})
+
+	t.Run("parses empty fields", func(t *testing.T) {
+		asrt := assert.New(t)
+		req := require.New(t)
+
+		label, ok := parseRecordLabel("<p>|| {} ")
+		req.True(ok)
+		req.Len(label.elements, 3)
+
+		asrt.Equal("p", label.elements[0].(*RecordField).GetPort().ID())
+		asrt.Empty(label.elements[0].(*RecordField).content)
+		asrt.Empty(label.elements[1].(*RecordField).content)
+		asrt.IsType(&RecordGroup{}, label.elements[2])
+	})
+
+	t.Run("round trips generated labels", func(t *testing.T) {
+		asrt := assert.New(t)
+		req := require.New(t)
+
+		original := Record(
+			Field("a|b").Port("p0"),
+			FieldGroup(Field("{x}"), FieldGroup(Field(`back\slash`).Port("deep"), Field("<y>"))),
+		)
+
+		label, ok := parseRecordLabel(original.String())
+		req.True(ok)
+
+		asrt.Equal(original.String(), label.String())
+	})
+
+	t.Run("rejects invalid syntax", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			label string
+		}{
+			{"unclosed group", "{a|b"},
+			{"unopened group", "a}|b"},
+			{"unclosed port", "<p a"},
+			{"text before group", "a {b}"},
+			{"text after group", "{a} b"},
+			{"unescaped angle bracket", "a > b"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, ok := parseRecordLabel(tt.label)
+				assert.False(t, ok, "expected invalid record syntax")
+			})
+		}
+	})
+}
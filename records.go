@@ -11,6 +11,7 @@ import (
 type RecordElement interface {
 	recordElement()
 	renderRecord() string
+	cloneRecord() RecordElement
 }
 
 // RecordField represents a single field in a record label.
@@ -53,6 +54,16 @@ func (f *RecordField) renderRecord() string {
 	return content
 }
 
+// cloneRecord returns a copy of this field with its own port.
+func (f *RecordField) cloneRecord() RecordElement {
+	copied := *f
+	if f.portRef != nil {
+		port := *f.portRef
+		copied.portRef = &port
+	}
+	return &copied
+}
+
 // RecordGroup represents a grouped set of record elements.
 // Groups are rendered wrapped in braces { }.
 type RecordGroup struct {
@@ -78,6 +89,11 @@ func (g *RecordGroup) renderRecord() string {
 	return "{ " + strings.Join(parts, " | ") + " }"
 }
 
+// cloneRecord returns a deep copy of this group.
+func (g *RecordGroup) cloneRecord() RecordElement {
+	return &RecordGroup{elements: cloneRecordElements(g.elements)}
+}
+
 // RecordLabel represents a complete record label containing multiple elements.
 type RecordLabel struct {
 	elements []RecordElement
@@ -108,6 +124,53 @@ func (l *RecordLabel) setNodeContext(nodeID string) {
 	}
 }
 
+// GetPort returns the port with the given ID defined by one of the label's fields,
+// including fields nested in groups, or nil if no field declares it.
+//
+// Example:
+//
+//	e := g.AddEdge(a, b, FromPort(a.Attrs().RecordLabel().GetPort("f2")))
+func (l *RecordLabel) GetPort(id string) *Port {
+	return l.findPort(id)
+}
+
+// findPort returns the port with the given ID defined in this label, or nil if none exists.
+func (l *RecordLabel) findPort(id string) *Port {
+	return findRecordPort(l.elements, id)
+}
+
+// clone returns a deep copy of the label with its own fields, groups and ports.
+func (l *RecordLabel) clone() *RecordLabel {
+	return &RecordLabel{elements: cloneRecordElements(l.elements)}
+}
+
+// cloneRecordElements deep copies a list of record elements.
+func cloneRecordElements(elements []RecordElement) []RecordElement {
+	copied := make([]RecordElement, len(elements))
+	for i, elem := range elements {
+		copied[i] = elem.cloneRecord()
+	}
+	return copied
+}
+
+// findRecordPort recursively searches a record element tree for the port with the given ID.
+func findRecordPort(elements []RecordElement, id string) *Port {
+	for _, elem := range elements {
+		switch e := elem.(type) {
+		case *RecordField:
+			if e.portRef != nil && e.portRef.id == id {
+				return e.portRef
+			}
+		case *RecordGroup:
+			if port := findRecordPort(e.elements, id); port != nil {
+				return port
+			}
+		}
+	}
+
+	return nil
+}
+
 // setPortContextRecursive recursively sets the node context for all ports in a record element tree.
 func setPortContextRecursive(elem RecordElement, nodeID string) {
 	switch e := elem.(type) {
@@ -123,13 +186,22 @@ func setPortContextRecursive(elem RecordElement, nodeID string) {
 }
 
 // escapeRecordString escapes special characters in record label strings.
-// The special characters |, {, }, <, > must be escaped with backslash.
+// The special characters |, {, }, <, > and backslashes must be escaped with backslash.
+// Label escapes such as \l are left as-is for Graphviz to interpret.
 func escapeRecordString(s string) string {
-	s = strings.ReplaceAll(s, "\\", "\\\\")
-	s = strings.ReplaceAll(s, "|", "\\|")
-	s = strings.ReplaceAll(s, "{", "\\{")
-	s = strings.ReplaceAll(s, "}", "\\}")
-	s = strings.ReplaceAll(s, "<", "\\<")
-	s = strings.ReplaceAll(s, ">", "\\>")
-	return s
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case isLabelEscape(s, i):
+			sb.WriteString(s[i : i+2])
+			i++
+		case ch == '\\' || strings.IndexByte(recordSpecialChars, ch) >= 0:
+			sb.WriteByte('\\')
+			sb.WriteByte(ch)
+		default:
+			sb.WriteByte(ch)
+		}
+	}
+
+	return sb.String()
 }
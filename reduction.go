@@ -0,0 +1,107 @@
+// ABOUTME: Implements transitive reduction of directed acyclic graphs, like Graphviz's tred.
+// ABOUTME: Removes edges implied by longer paths while keeping attributes and subgraphs.
+package goraffe
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// TransitiveReduction returns a copy of a directed acyclic graph with every edge that is
+// implied by a longer path removed, producing the same result as Graphviz's tred tool.
+// When several parallel edges join the same pair of nodes, only the first is kept.
+// Node and edge attributes, graph defaults and subgraph membership are preserved;
+// the returned graph shares its Node and Edge values with g.
+// Returns ErrUndirectedGraph for undirected graphs and a *CycleError if g has a cycle.
+//
+// Example:
+//
+//	reduced, err := goraffe.TransitiveReduction(g)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	reduced.RenderToFile(goraffe.SVG, "deps.svg")
+func TransitiveReduction(g *Graph) (*Graph, error) {
+	redundant, err := g.redundantEdges()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.project(
+		func(*Node) bool { return true },
+		func(e *Edge) bool { return !redundant[e] },
+	), nil
+}
+
+// TransitiveReductionInPlace removes every edge implied by a longer path from a directed
+// acyclic graph, modifying g directly. See TransitiveReduction for details.
+// The graph is left unchanged if an error is returned.
+func TransitiveReductionInPlace(g *Graph) error {
+	redundant, err := g.redundantEdges()
+	if err != nil {
+		return err
+	}
+
+	g.removeEdges(slices.Collect(maps.Keys(redundant)))
+
+	return nil
+}
+
+// redundantEdges returns the set of edges removed by a transitive reduction.
+// An edge u->v is redundant if v is reachable from u through another of u's successors,
+// or if it duplicates an earlier u->v edge.
+func (g *Graph) redundantEdges() (map[*Edge]bool, error) {
+	if !g.directed {
+		return nil, fmt.Errorf("could not reduce graph: %w", ErrUndirectedGraph)
+	}
+
+	if _, err := g.TopologicalSort(); err != nil {
+		return nil, err
+	}
+
+	redundant := make(map[*Edge]bool)
+	for _, n := range g.nodeOrder {
+		indirect := g.indirectlyReachable(n.ID())
+		direct := make(map[string]bool)
+
+		for _, e := range g.outEdges[n.ID()] {
+			target := e.to.ID()
+			if indirect[target] || direct[target] {
+				redundant[e] = true
+				continue
+			}
+			direct[target] = true
+		}
+	}
+
+	return redundant, nil
+}
+
+// indirectlyReachable returns the IDs of nodes reachable from the given node by a path
+// of two or more edges.
+func (g *Graph) indirectlyReachable(id string) map[string]bool {
+	reached := make(map[string]bool)
+	pending := make([]string, 0)
+
+	for _, successor := range g.outEdges[id] {
+		for _, e := range g.outEdges[successor.to.ID()] {
+			pending = append(pending, e.to.ID())
+		}
+	}
+
+	for len(pending) > 0 {
+		next := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if reached[next] {
+			continue
+		}
+		reached[next] = true
+
+		for _, e := range g.outEdges[next] {
+			pending = append(pending, e.to.ID())
+		}
+	}
+
+	return reached
+}
@@ -0,0 +1,133 @@
+// ABOUTME: Tests for transitive reduction of directed acyclic graphs.
+// ABOUTME: Verifies redundant edges are removed while attributes and subgraphs are kept.
+package goraffe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func edgePairs(edges []*Edge) []string {
+	pairs := make([]string, len(edges))
+	for i, e := range edges {
+		pairs[i] = e.From().ID() + "->" + e.To().ID()
+	}
+	return pairs
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	t.Run("removes implied edges", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := buildDirected(
+			[2]string{"A", "B"}, [2]string{"B", "C"}, [2]string{"A", "C"},
+			[2]string{"C", "D"}, [2]string{"A", "D"}, [2]string{"B", "D"},
+		)
+
+		reduced, err := TransitiveReduction(g)
+
+		asrt.NoError(err)
+		asrt.Equal([]string{"A->B", "B->C", "C->D"}, edgePairs(reduced.Edges()))
+		asrt.Len(g.Edges(), 6, "expected the original graph to be untouched")
+	})
+
+	t.Run("keeps only the first parallel edge", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := buildDirected([2]string{"A", "B"}, [2]string{"A", "B"})
+		first := g.Edges()[0]
+
+		reduced, err := TransitiveReduction(g)
+
+		asrt.NoError(err)
+		asrt.Equal([]*Edge{first}, reduced.Edges())
+	})
+
+	t.Run("preserves attributes and subgraphs", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed, WithName("deps"), WithRankDir(RankDirLR), WithDefaultNodeAttrs(WithBoxShape()))
+		a := NewNode("A", WithLabel("alpha"))
+		var b, c *Node
+		g.Subgraph("cluster_lib", func(s *Subgraph) {
+			s.SetLabel("lib")
+			b, c = NewNode("B"), NewNode("C")
+			_, _ = s.AddEdge(b, c, WithEdgeColor("blue"))
+		})
+		g.Subgraph("cluster_empty", func(*Subgraph) {})
+		_, _ = g.AddEdge(a, b)
+		_, _ = g.AddEdge(a, c)
+
+		reduced, err := TransitiveReduction(g)
+
+		asrt.NoError(err)
+		asrt.Equal("deps", reduced.Name())
+		asrt.True(reduced.IsDirected())
+		asrt.Equal(RankDirLR, reduced.Attrs().RankDir())
+		asrt.Equal(ShapeBox, reduced.DefaultNodeAttrs().Shape())
+		asrt.Equal("alpha", reduced.GetNode("A").Attrs().Label())
+		asrt.Equal([]string{"B->C", "A->B"}, edgePairs(reduced.Edges()))
+		asrt.Len(reduced.Subgraphs(), 1, "expected subgraphs without nodes to be dropped")
+		lib := reduced.Subgraphs()[0]
+		asrt.Equal("lib", lib.Attrs().Label())
+		asrt.Len(lib.Nodes(), 2)
+		asrt.Len(lib.Edges(), 1)
+		asrt.Equal("blue", lib.Edges()[0].Attrs().Color())
+	})
+
+	t.Run("reports cycles", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := buildDirected([2]string{"A", "B"}, [2]string{"B", "A"})
+
+		reduced, err := TransitiveReduction(g)
+
+		asrt.Nil(reduced)
+		asrt.ErrorIs(err, ErrCycle)
+	})
+
+	t.Run("rejects undirected graphs", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		_, _ = g.AddEdge(NewNode("A"), NewNode("B"))
+
+		_, err := TransitiveReduction(g)
+
+		asrt.ErrorIs(err, ErrUndirectedGraph)
+	})
+}
+
+func TestTransitiveReductionInPlace(t *testing.T) {
+	t.Run("removes implied edges from the graph", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		var sg *Subgraph
+		g := NewGraph(Directed)
+		g.Subgraph("cluster_0", func(s *Subgraph) {
+			sg = s
+			_, _ = s.AddEdge(NewNode("A"), NewNode("C"))
+		})
+		_, _ = g.AddEdge(g.GetNode("A"), NewNode("B"))
+		_, _ = g.AddEdge(g.GetNode("B"), g.GetNode("C"))
+
+		err := TransitiveReductionInPlace(g)
+
+		asrt.NoError(err)
+		asrt.Equal([]string{"A->B", "B->C"}, edgePairs(g.Edges()))
+		asrt.Empty(sg.Edges(), "expected the redundant edge to leave its subgraph")
+		asrt.Empty(g.EdgesBetween("A", "C"), "expected the adjacency index to be updated")
+	})
+
+	t.Run("leaves cyclic graphs unchanged", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := buildDirected([2]string{"A", "B"}, [2]string{"B", "C"}, [2]string{"A", "C"}, [2]string{"C", "A"})
+
+		err := TransitiveReductionInPlace(g)
+
+		asrt.ErrorIs(err, ErrCycle)
+		asrt.Len(g.Edges(), 4)
+	})
+}
@@ -10,11 +10,15 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 )
 
 // Format represents the output format for rendered graphs.
 type Format string
 
+// A Format may also name a Graphviz renderer and formatter, as in "png:cairo" or
+// "png:cairo:gd"; see Format.Variant. Which formats are available depends on the
+// plugins of the installed Graphviz, which SupportedFormats reports.
 const (
 	// PNG produces PNG (Portable Network Graphics) raster images.
 	PNG Format = "png"
@@ -24,6 +28,44 @@ const (
 	PDF Format = "pdf"
 	// DOT produces DOT language source code.
 	DOT Format = "dot"
+	// JPEG produces JPEG raster images.
+	JPEG Format = "jpeg"
+	// GIF produces GIF raster images.
+	GIF Format = "gif"
+	// WEBP produces WebP raster images.
+	WEBP Format = "webp"
+	// BMP produces Windows bitmap images.
+	BMP Format = "bmp"
+	// TIFF produces TIFF raster images.
+	TIFF Format = "tiff"
+	// PS produces PostScript documents.
+	PS Format = "ps"
+	// EPS produces Encapsulated PostScript for embedding in other documents.
+	EPS Format = "eps"
+	// SVGZ produces gzip-compressed SVG.
+	SVGZ Format = "svgz"
+	// JSON produces the laid-out graph as JSON, including drawing operations.
+	JSON Format = "json"
+	// JSON0 produces the laid-out graph as JSON, without drawing operations.
+	JSON0 Format = "json0"
+	// XDOT produces DOT annotated with layout positions and drawing operations.
+	XDOT Format = "xdot"
+	// XDOTJSON produces the xdot drawing operations as JSON.
+	XDOTJSON Format = "xdot_json"
+	// Plain produces a simple line-based text description of the layout.
+	Plain Format = "plain"
+	// PlainExt produces the Plain layout description with edge ports.
+	PlainExt Format = "plain-ext"
+	// Canon produces DOT source in canonical form, without layout information.
+	Canon Format = "canon"
+	// IMAP produces a server-side image map.
+	IMAP Format = "imap"
+	// CMAPX produces a client-side image map for use in HTML.
+	CMAPX Format = "cmapx"
+	// FIG produces XFIG graphics.
+	FIG Format = "fig"
+	// VRML produces VRML 3D scenes.
+	VRML Format = "vrml"
 )
 
 // Layout represents the graph layout algorithm to use.
@@ -62,14 +104,23 @@ func findGraphviz(layout Layout) (string, error) {
 // GraphvizVersion returns the version of Graphviz installed.
 // Returns the version string or an error if Graphviz is not found.
 func GraphvizVersion() (string, error) {
+	return GraphvizVersionContext(context.Background())
+}
+
+// GraphvizVersionContext is like GraphvizVersion, but kills the dot process and
+// returns an error wrapping ctx.Err() if ctx is done before it reports its version.
+func GraphvizVersionContext(ctx context.Context) (string, error) {
 	// Try to find dot first
 	if _, err := findGraphviz(LayoutDot); err != nil {
 		return "", err
 	}
 
 	// Run "dot -V" to get version
-	cmd := exec.CommandContext(context.TODO(), "dot", "-V")
+	cmd := exec.CommandContext(ctx, "dot", "-V")
 	output, err := cmd.CombinedOutput()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return "", fmt.Errorf("failed to get Graphviz version: %w", ctxErr)
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to get Graphviz version: %w", err)
 	}
@@ -93,7 +144,8 @@ type RenderOption interface {
 
 // renderConfig holds rendering configuration.
 type renderConfig struct {
-	layout Layout
+	layout  Layout
+	timeout time.Duration
 }
 
 // layoutOption implements RenderOption to set the layout engine.
@@ -111,9 +163,46 @@ func WithLayout(l Layout) RenderOption {
 	return layoutOption{layout: l}
 }
 
+// timeoutOption implements RenderOption to limit how long Graphviz may run.
+type timeoutOption struct {
+	timeout time.Duration
+}
+
+func (o timeoutOption) applyRender(cfg *renderConfig) {
+	cfg.timeout = o.timeout
+}
+
+// WithTimeout limits how long Graphviz may run. If rendering takes longer, the Graphviz
+// process is killed and the render returns an error wrapping context.DeadlineExceeded.
+// With RenderContext the earlier of the timeout and the context's deadline applies.
+// A zero or negative duration means no timeout, which is the default.
+//
+// Example:
+//
+//	err := g.Render(goraffe.SVG, w, goraffe.WithTimeout(5*time.Second))
+func WithTimeout(d time.Duration) RenderOption {
+	return timeoutOption{timeout: d}
+}
+
 // Render renders the graph to the given writer in the specified format.
 // Uses the Graphviz layout engine specified by options (default: dot).
+// Returns a RenderError wrapping ErrUnsupportedFormat, without running the layout,
+// if the installed Graphviz does not support the format.
 func (g *Graph) Render(format Format, w io.Writer, opts ...RenderOption) error {
+	return g.RenderContext(context.Background(), format, w, opts...)
+}
+
+// RenderContext is like Render, but kills the Graphviz process if ctx is done before
+// rendering completes. The returned error then wraps both ErrRenderFailed and ctx.Err(),
+// so callers can test for context.DeadlineExceeded or context.Canceled with errors.Is.
+// Nothing is written to w unless rendering succeeds.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+//	defer cancel()
+//	err := g.RenderContext(ctx, goraffe.SVG, w)
+func (g *Graph) RenderContext(ctx context.Context, format Format, w io.Writer, opts ...RenderOption) error {
 	// Build config with defaults
 	config := &renderConfig{
 		layout: LayoutDot,
@@ -128,12 +217,22 @@ func (g *Graph) Render(format Format, w io.Writer, opts ...RenderOption) error {
 		return err
 	}
 
+	if config.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.timeout)
+		defer cancel()
+	}
+
+	if err := checkFormat(ctx, binary, format); err != nil {
+		return err
+	}
+
 	// Generate DOT string
 	dotString := g.String()
 
 	// Execute Graphviz command: binary -Tformat
 	//nolint:gosec // G204: binary path is validated via exec.LookPath in findGraphviz
-	cmd := exec.CommandContext(context.TODO(), binary, "-T"+string(format))
+	cmd := exec.CommandContext(ctx, binary, "-T"+string(format))
 	cmd.Stdin = strings.NewReader(dotString)
 
 	var stdout, stderr bytes.Buffer
@@ -142,9 +241,13 @@ func (g *Graph) Render(format Format, w io.Writer, opts ...RenderOption) error {
 
 	// Run the command
 	if err := cmd.Run(); err != nil {
-		// Wrap error with stderr output
+		// Wrap error with stderr output, and with the reason if Graphviz was killed
+		renderErr := ErrRenderFailed
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			renderErr = fmt.Errorf("%w: %w", ErrRenderFailed, ctxErr)
+		}
 		return &RenderError{
-			Err:      ErrRenderFailed,
+			Err:      renderErr,
 			Stderr:   stderr.String(),
 			ExitCode: cmd.ProcessState.ExitCode(),
 		}
@@ -158,6 +261,12 @@ func (g *Graph) Render(format Format, w io.Writer, opts ...RenderOption) error {
 // RenderToFile renders the graph to a file in the specified format.
 // Creates the file, renders to it, and closes it. On error, attempts to clean up the partial file.
 func (g *Graph) RenderToFile(format Format, path string, opts ...RenderOption) error {
+	return g.RenderToFileContext(context.Background(), format, path, opts...)
+}
+
+// RenderToFileContext is like RenderToFile, but stops rendering if ctx is done.
+// See RenderContext for how cancellation is reported.
+func (g *Graph) RenderToFileContext(ctx context.Context, format Format, path string, opts ...RenderOption) error {
 	// Create the file
 	//nolint:gosec // G304: RenderToFile intentionally creates user-specified files
 	file, err := os.Create(path)
@@ -166,7 +275,7 @@ func (g *Graph) RenderToFile(format Format, path string, opts ...RenderOption) e
 	}
 
 	// Render to file
-	renderErr := g.Render(format, file, opts...)
+	renderErr := g.RenderContext(ctx, format, file, opts...)
 
 	// Close the file
 	closeErr := file.Close()
@@ -183,8 +292,14 @@ func (g *Graph) RenderToFile(format Format, path string, opts ...RenderOption) e
 
 // RenderBytes renders the graph and returns the output as a byte slice.
 func (g *Graph) RenderBytes(format Format, opts ...RenderOption) ([]byte, error) {
+	return g.RenderBytesContext(context.Background(), format, opts...)
+}
+
+// RenderBytesContext is like RenderBytes, but stops rendering if ctx is done.
+// See RenderContext for how cancellation is reported.
+func (g *Graph) RenderBytesContext(ctx context.Context, format Format, opts ...RenderOption) ([]byte, error) {
 	var buf bytes.Buffer
-	err := g.Render(format, &buf, opts...)
+	err := g.RenderContext(ctx, format, &buf, opts...)
 	if err != nil {
 		return nil, err
 	}
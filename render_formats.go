@@ -0,0 +1,167 @@
+// ABOUTME: Describes Graphviz output format variants and checks them against the installed Graphviz.
+// ABOUTME: SupportedFormats asks Graphviz which formats its plugins provide, so renders can fail fast.
+package goraffe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// supportedFormatsCache maps a Graphviz binary path to the formats it reported.
+var supportedFormatsCache sync.Map
+
+// Base returns the output format without any renderer or formatter, such as "png"
+// for "png:cairo:gd".
+func (f Format) Base() Format {
+	base, _, _ := strings.Cut(string(f), ":")
+	return Format(base)
+}
+
+// Renderer returns the renderer named by a format variant, such as "cairo" for
+// "png:cairo", or an empty string if the format does not name one.
+func (f Format) Renderer() string {
+	return f.part(1)
+}
+
+// Formatter returns the formatter named by a format variant, such as "gd" for
+// "png:cairo:gd", or an empty string if the format does not name one.
+func (f Format) Formatter() string {
+	return f.part(2)
+}
+
+// part returns the i'th colon-separated part of the format, or an empty string.
+func (f Format) part(i int) string {
+	parts := strings.Split(string(f), ":")
+	if i < len(parts) {
+		return parts[i]
+	}
+	return ""
+}
+
+// Variant returns the format produced by a specific Graphviz renderer and, if formatter
+// is not empty, formatter. Any renderer or formatter already named by f is replaced.
+//
+// Example:
+//
+//	g.Render(goraffe.PNG.Variant("cairo", ""), w)
+func (f Format) Variant(renderer, formatter string) Format {
+	variant := string(f.Base()) + ":" + renderer
+	if formatter != "" {
+		variant += ":" + formatter
+	}
+	return Format(variant)
+}
+
+// Validate checks that the format is well formed: a format name, optionally followed by
+// a renderer and a formatter, separated by colons. It does not check that Graphviz
+// supports the format; see SupportedFormats.
+// Returns an error wrapping ErrUnsupportedFormat if the format is malformed.
+func (f Format) Validate() error {
+	parts := strings.Split(string(f), ":")
+	if len(parts) > 3 {
+		return fmt.Errorf("%w %q: expected format[:renderer[:formatter]]", ErrUnsupportedFormat, f)
+	}
+
+	for _, part := range parts {
+		if part == "" || strings.IndexFunc(part, isInvalidFormatRune) >= 0 {
+			return fmt.Errorf("%w %q: expected format[:renderer[:formatter]]", ErrUnsupportedFormat, f)
+		}
+	}
+
+	return nil
+}
+
+// isInvalidFormatRune reports whether r cannot appear in a format, renderer or formatter name.
+func isInvalidFormatRune(r rune) bool {
+	return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("._-", r))
+}
+
+// SupportedFormats returns the output formats the installed Graphviz supports, as
+// reported by "dot -T?". The list depends on the plugins Graphviz was built with and
+// names base formats only; renderer variants are not listed.
+// Returns ErrGraphvizNotFound if Graphviz is not installed.
+func SupportedFormats() ([]Format, error) {
+	return SupportedFormatsContext(context.Background())
+}
+
+// SupportedFormatsContext is like SupportedFormats, but kills the dot process and
+// returns an error wrapping ctx.Err() if ctx is done before it reports its formats.
+func SupportedFormatsContext(ctx context.Context) ([]Format, error) {
+	binary, err := findGraphviz(LayoutDot)
+	if err != nil {
+		return nil, err
+	}
+
+	return supportedFormats(ctx, binary)
+}
+
+// supportedFormats returns the formats reported by the given Graphviz binary, caching
+// the answer for the life of the process.
+func supportedFormats(ctx context.Context, binary string) ([]Format, error) {
+	if cached, ok := supportedFormatsCache.Load(binary); ok {
+		return slices.Clone(cached.([]Format)), nil
+	}
+
+	// Graphviz lists its formats when asked for one it does not know, exiting with an error
+	//nolint:gosec // G204: binary path is validated via exec.LookPath in findGraphviz
+	output, _ := exec.CommandContext(ctx, binary, "-T?").CombinedOutput()
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list Graphviz formats: %w", err)
+	}
+
+	formats, err := parseSupportedFormats(string(output))
+	if err != nil {
+		return nil, err
+	}
+
+	supportedFormatsCache.Store(binary, formats)
+	return slices.Clone(formats), nil
+}
+
+// parseSupportedFormats extracts the format list from the output of "dot -T?", which
+// ends with "Use one of: bmp canon ...".
+func parseSupportedFormats(output string) ([]Format, error) {
+	_, list, found := strings.Cut(output, "Use one of:")
+	if !found {
+		return nil, errors.New("failed to list Graphviz formats: unexpected output from dot -T?")
+	}
+
+	var formats []Format
+	for _, name := range strings.Fields(list) {
+		format := Format(name).Base()
+		if !slices.Contains(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+
+	return formats, nil
+}
+
+// checkFormat returns a RenderError wrapping ErrUnsupportedFormat if the format is
+// malformed or the Graphviz binary does not support it. If the supported formats cannot
+// be determined, the format is left for Graphviz itself to accept or reject.
+func checkFormat(ctx context.Context, binary string, format Format) error {
+	if err := format.Validate(); err != nil {
+		return &RenderError{Err: err, ExitCode: -1}
+	}
+
+	supported, err := supportedFormats(ctx, binary)
+	if err != nil || slices.Contains(supported, format.Base()) {
+		return nil
+	}
+
+	names := make([]string, len(supported))
+	for i, f := range supported {
+		names[i] = string(f)
+	}
+
+	return &RenderError{
+		Err:      fmt.Errorf("%w %q; Graphviz supports: %s", ErrUnsupportedFormat, format, strings.Join(names, " ")),
+		ExitCode: -1,
+	}
+}
@@ -0,0 +1,137 @@
+// ABOUTME: Tests for Graphviz output format variants, validation and SupportedFormats.
+// ABOUTME: Uses a fake dot script so format discovery can be tested without Graphviz.
+package goraffe
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeGraphvizFormats fakes a Graphviz that lists the given formats for -T? and
+// otherwise echoes its input.
+func fakeGraphvizFormats(t *testing.T, formats string) {
+	t.Helper()
+	fakeGraphviz(t, `if [ "$1" = "-T?" ]; then
+  echo 'Format: "?" not recognized. Use one of: `+formats+`' >&2
+  exit 1
+fi
+exec cat`)
+}
+
+func TestFormat_Variants(t *testing.T) {
+	t.Run("splits renderer and formatter", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		f := Format("png:cairo:gd")
+
+		asrt.Equal(PNG, f.Base())
+		asrt.Equal("cairo", f.Renderer())
+		asrt.Equal("gd", f.Formatter())
+		asrt.Equal(SVG, SVG.Base())
+		asrt.Empty(SVG.Renderer())
+		asrt.Empty(Format("png:cairo").Formatter())
+	})
+
+	t.Run("builds variants from a base format", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		asrt.Equal(Format("png:cairo"), PNG.Variant("cairo", ""))
+		asrt.Equal(Format("png:cairo:gd"), PNG.Variant("cairo", "gd"))
+		asrt.Equal(Format("png:gd"), Format("png:cairo:gd").Variant("gd", ""))
+	})
+}
+
+func TestFormat_Validate(t *testing.T) {
+	t.Run("accepts formats and variants", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		for _, f := range []Format{PNG, PlainExt, XDOTJSON, "xdot1.4", "png:cairo", "png:cairo:gd"} {
+			asrt.NoError(f.Validate(), "format %q", f)
+		}
+	})
+
+	t.Run("rejects malformed formats", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		for _, f := range []Format{"", "png:", ":cairo", "png:cairo:gd:x", "png cairo", "-Tpng;rm"} {
+			asrt.ErrorIs(f.Validate(), ErrUnsupportedFormat, "format %q", f)
+		}
+	})
+}
+
+func TestSupportedFormats(t *testing.T) {
+	t.Run("lists the formats Graphviz reports", func(t *testing.T) {
+		asrt := assert.New(t)
+		fakeGraphvizFormats(t, "canon dot png svg")
+
+		formats, err := SupportedFormats()
+
+		asrt.NoError(err)
+		asrt.Equal([]Format{Canon, DOT, PNG, SVG}, formats)
+	})
+
+	t.Run("reports a missing Graphviz", func(t *testing.T) {
+		asrt := assert.New(t)
+		t.Setenv("PATH", t.TempDir())
+
+		_, err := SupportedFormats()
+
+		asrt.ErrorIs(err, ErrGraphvizNotFound)
+	})
+
+	t.Run("reports unexpected output", func(t *testing.T) {
+		asrt := assert.New(t)
+		fakeGraphviz(t, "echo usage: dot")
+
+		_, err := SupportedFormats()
+
+		asrt.Error(err)
+	})
+
+	t.Run("reports a real Graphviz's formats", func(t *testing.T) {
+		requireGraphviz(t)
+		asrt := assert.New(t)
+
+		formats, err := SupportedFormats()
+
+		asrt.NoError(err)
+		asrt.Contains(formats, SVG)
+		asrt.Contains(formats, DOT)
+	})
+}
+
+func TestGraph_Render_CheckFormat(t *testing.T) {
+	t.Run("fails fast on unsupported formats", func(t *testing.T) {
+		asrt := assert.New(t)
+		fakeGraphvizFormats(t, "dot svg")
+
+		out, err := NewGraph().RenderBytes(WEBP)
+
+		asrt.Nil(out)
+		asrt.ErrorIs(err, ErrUnsupportedFormat)
+		var renderErr *RenderError
+		asrt.True(errors.As(err, &renderErr))
+		asrt.Equal(`goraffe: unsupported output format "webp"; Graphviz supports: dot svg`, err.Error())
+	})
+
+	t.Run("accepts variants of supported formats", func(t *testing.T) {
+		asrt := assert.New(t)
+		fakeGraphvizFormats(t, "dot svg")
+
+		_, err := NewGraph().RenderBytesContext(context.Background(), SVG.Variant("core", ""))
+
+		asrt.NoError(err)
+	})
+
+	t.Run("rejects malformed formats", func(t *testing.T) {
+		asrt := assert.New(t)
+		fakeGraphvizFormats(t, "dot svg")
+
+		_, err := NewGraph().RenderBytes("svg:")
+
+		asrt.ErrorIs(err, ErrUnsupportedFormat)
+	})
+}
@@ -1,11 +1,16 @@
 package goraffe
 
 import (
+	"context"
 	"errors"
 	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // requireGraphviz skips the test if Graphviz is not installed.
@@ -16,6 +21,21 @@ func requireGraphviz(t *testing.T) {
 	}
 }
 
+// fakeGraphviz puts a "dot" shell script running body first on PATH, so render
+// behavior can be tested without Graphviz.
+func fakeGraphviz(t *testing.T, body string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake Graphviz requires a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" + body + "\n"
+	//nolint:gosec // G306: the fake binary must be executable
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dot"), []byte(script), 0o700))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
 func TestFormat_StringValues(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -26,6 +46,12 @@ func TestFormat_StringValues(t *testing.T) {
 		{"SVG format", SVG, "svg"},
 		{"PDF format", PDF, "pdf"},
 		{"DOT format", DOT, "dot"},
+		{"JPEG format", JPEG, "jpeg"},
+		{"WEBP format", WEBP, "webp"},
+		{"JSON0 format", JSON0, "json0"},
+		{"XDOTJSON format", XDOTJSON, "xdot_json"},
+		{"PlainExt format", PlainExt, "plain-ext"},
+		{"CMAPX format", CMAPX, "cmapx"},
 	}
 
 	for _, tt := range tests {
@@ -508,3 +534,76 @@ func TestGoraffe_EndToEnd(t *testing.T) {
 		assert.Contains(t, string(data), "Extra Node")
 	})
 }
+
+func TestGraph_RenderContext(t *testing.T) {
+	t.Run("passes the DOT source through Graphviz", func(t *testing.T) {
+		asrt := assert.New(t)
+		fakeGraphviz(t, "exec cat")
+
+		g := NewGraph(Directed)
+		_, _ = g.AddEdge(NewNode("A"), NewNode("B"))
+
+		out, err := g.RenderBytesContext(context.Background(), DOT, WithTimeout(10*time.Second))
+
+		asrt.NoError(err)
+		asrt.Equal(g.String(), string(out))
+	})
+
+	t.Run("WithTimeout kills a hanging Graphviz", func(t *testing.T) {
+		asrt := assert.New(t)
+		fakeGraphviz(t, "exec sleep 10")
+
+		start := time.Now()
+		out, err := NewGraph().RenderBytes(SVG, WithTimeout(50*time.Millisecond))
+
+		asrt.Nil(out)
+		asrt.ErrorIs(err, context.DeadlineExceeded)
+		asrt.ErrorIs(err, ErrRenderFailed)
+		var renderErr *RenderError
+		asrt.ErrorAs(err, &renderErr)
+		asrt.Less(time.Since(start), 5*time.Second)
+	})
+
+	t.Run("stops when the context is canceled", func(t *testing.T) {
+		asrt := assert.New(t)
+		fakeGraphviz(t, "exec sleep 10")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		var buf []byte
+		err := NewGraph().RenderContext(ctx, SVG, &testWriter{buf: &buf})
+
+		asrt.ErrorIs(err, context.Canceled)
+		asrt.Empty(buf)
+	})
+
+	t.Run("removes the partial file on timeout", func(t *testing.T) {
+		asrt := assert.New(t)
+		fakeGraphviz(t, "exec sleep 10")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		path := filepath.Join(t.TempDir(), "out.svg")
+
+		err := NewGraph().RenderToFileContext(ctx, SVG, path)
+
+		asrt.ErrorIs(err, context.DeadlineExceeded)
+		asrt.NoFileExists(path)
+	})
+}
+
+func TestGraphvizVersionContext(t *testing.T) {
+	t.Run("stops when the context is done", func(t *testing.T) {
+		asrt := assert.New(t)
+		fakeGraphviz(t, "exec sleep 10")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		version, err := GraphvizVersionContext(ctx)
+
+		asrt.Empty(version)
+		asrt.ErrorIs(err, context.DeadlineExceeded)
+	})
+}
@@ -3,7 +3,7 @@
 package goraffe
 
 import (
-	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -15,12 +15,16 @@ import (
 // and fill colors. Regular subgraphs may have these attributes set but they typically won't
 // be rendered by Graphviz.
 type Subgraph struct {
-	name      string
-	nodes     map[string]*Node
-	edges     []*Edge
-	parent    *Graph
-	attrs     *SubgraphAttributes
-	subgraphs []*Subgraph
+	name             string
+	nodes            map[string]int
+	nodeOrder        []*Node
+	edges            edgeList
+	parent           *Graph
+	attrs            *SubgraphAttributes
+	defaultNodeAttrs *NodeAttributes
+	defaultEdgeAttrs *EdgeAttributes
+	subgraphs        []*Subgraph
+	comment          string
 }
 
 // Name returns the name of the subgraph.
@@ -41,18 +45,25 @@ func (sg *Subgraph) AddNode(n *Node) error {
 		return ErrNilNode
 	}
 
-	sg.nodes[n.ID()] = n
+	sg.addNode(n)
 	return sg.parent.AddNode(n)
 }
 
-// Nodes returns all nodes in the subgraph.
-// The returned slice contains nodes in no particular order.
-func (sg *Subgraph) Nodes() []*Node {
-	nodes := make([]*Node, 0, len(sg.nodes))
-	for _, node := range sg.nodes {
-		nodes = append(nodes, node)
+// addNode records n as a member of the subgraph. A node with the same ID is replaced
+// in place, preserving its original position in the node order.
+func (sg *Subgraph) addNode(n *Node) {
+	if idx, exists := sg.nodes[n.ID()]; exists {
+		sg.nodeOrder[idx] = n
+	} else {
+		sg.nodes[n.ID()] = len(sg.nodeOrder)
+		sg.nodeOrder = append(sg.nodeOrder, n)
 	}
-	return nodes
+}
+
+// Nodes returns all nodes in the subgraph in insertion order.
+// The returned slice should not be modified.
+func (sg *Subgraph) Nodes() []*Node {
+	return sg.nodeOrder
 }
 
 // AddEdge creates and adds an edge between two nodes, delegating to the parent graph.
@@ -63,23 +74,55 @@ func (sg *Subgraph) AddEdge(from, to *Node, opts ...EdgeOption) (*Edge, error) {
 	if err != nil {
 		return nil, err
 	}
-	sg.edges = append(sg.edges, edge)
+	// Strict graphs may return an edge this subgraph already holds, or drop a self-loop
+	if edge != nil && !sg.edges.contains(edge) {
+		sg.edges.add(edge)
+	}
 
 	// Add nodes to subgraph if not already present
 	if _, exists := sg.nodes[from.ID()]; !exists {
-		sg.nodes[from.ID()] = from
+		sg.addNode(from)
 	}
 	if _, exists := sg.nodes[to.ID()]; !exists {
-		sg.nodes[to.ID()] = to
+		sg.addNode(to)
 	}
 
 	return edge, nil
 }
 
+// removeNode removes the node with the given ID from this subgraph and all nested subgraphs.
+func (sg *Subgraph) removeNode(id string) {
+	if idx, exists := sg.nodes[id]; exists {
+		sg.nodeOrder = slices.Delete(sg.nodeOrder, idx, idx+1)
+		delete(sg.nodes, id)
+
+		// Shift the indices of every node that followed the removed one
+		for i := idx; i < len(sg.nodeOrder); i++ {
+			sg.nodes[sg.nodeOrder[i].ID()] = i
+		}
+	}
+
+	for _, nested := range sg.subgraphs {
+		nested.removeNode(id)
+	}
+}
+
+// removeEdges removes the given edges from this subgraph and all nested subgraphs,
+// preserving the order of the remaining edges.
+func (sg *Subgraph) removeEdges(edges []*Edge) {
+	for _, e := range edges {
+		sg.edges.remove(e)
+	}
+
+	for _, nested := range sg.subgraphs {
+		nested.removeEdges(edges)
+	}
+}
+
 // Edges returns all edges in the subgraph.
 // The returned slice contains edges in the order they were added.
 func (sg *Subgraph) Edges() []*Edge {
-	return sg.edges
+	return sg.edges.slice()
 }
 
 // Attrs returns the subgraph's attribute configuration.
@@ -135,6 +178,54 @@ func (sg *Subgraph) SetAttribute(key, value string) {
 	sg.Attrs().setCustom(key, value)
 }
 
+// SetDefaultNodeAttrs sets default attributes for nodes declared within the subgraph,
+// replacing any defaults set previously. They are rendered as a node [...] statement
+// inside the subgraph, so Graphviz applies them only to nodes first mentioned there.
+// Individual node attributes can override these defaults.
+//
+// Example:
+//
+//	g.Subgraph("cluster_db", func(s *Subgraph) {
+//		s.SetDefaultNodeAttrs(WithBoxShape(), WithFillColor("lightgray"))
+//		s.AddNode(NewNode("primary"))
+//	})
+func (sg *Subgraph) SetDefaultNodeAttrs(options ...NodeOption) {
+	sg.defaultNodeAttrs = &NodeAttributes{}
+
+	for _, option := range options {
+		option.applyNode(sg.defaultNodeAttrs)
+	}
+}
+
+// DefaultNodeAttrs returns the default attributes for nodes declared within the subgraph.
+// If defaults haven't been initialized yet, this creates and returns a new NodeAttributes.
+func (sg *Subgraph) DefaultNodeAttrs() *NodeAttributes {
+	if sg.defaultNodeAttrs == nil {
+		sg.defaultNodeAttrs = &NodeAttributes{}
+	}
+	return sg.defaultNodeAttrs
+}
+
+// SetDefaultEdgeAttrs sets default attributes for edges declared within the subgraph,
+// replacing any defaults set previously. They are rendered as an edge [...] statement
+// inside the subgraph. Individual edge attributes can override these defaults.
+func (sg *Subgraph) SetDefaultEdgeAttrs(options ...EdgeOption) {
+	sg.defaultEdgeAttrs = &EdgeAttributes{}
+
+	for _, option := range options {
+		option.applyEdge(sg.defaultEdgeAttrs)
+	}
+}
+
+// DefaultEdgeAttrs returns the default attributes for edges declared within the subgraph.
+// If defaults haven't been initialized yet, this creates and returns a new EdgeAttributes.
+func (sg *Subgraph) DefaultEdgeAttrs() *EdgeAttributes {
+	if sg.defaultEdgeAttrs == nil {
+		sg.defaultEdgeAttrs = &EdgeAttributes{}
+	}
+	return sg.defaultEdgeAttrs
+}
+
 // Subgraph creates a nested subgraph within this subgraph.
 // The nested subgraph will reference the root graph for node tracking, ensuring all nodes
 // are registered at the graph level while maintaining the subgraph hierarchy for DOT output.
@@ -151,8 +242,7 @@ func (sg *Subgraph) SetAttribute(key, value string) {
 func (sg *Subgraph) Subgraph(name string, fn func(*Subgraph)) *Subgraph {
 	nested := &Subgraph{
 		name:      name,
-		nodes:     make(map[string]*Node),
-		edges:     make([]*Edge, 0),
+		nodes:     make(map[string]int),
 		parent:    sg.parent, // Reference root graph for node tracking
 		subgraphs: make([]*Subgraph, 0),
 	}
@@ -171,39 +261,37 @@ func (sg *Subgraph) Subgraphs() []*Subgraph {
 }
 
 // String returns the DOT representation of the subgraph.
-// The output includes the subgraph declaration, attributes, nodes, and edges.
+// The output includes the subgraph declaration, attributes, default attributes, nested
+// subgraphs, nodes, and edges, exactly as the subgraph appears within Graph.String,
+// without indentation.
 func (sg *Subgraph) String() string {
 	builder := strings.Builder{}
+	defaultPrinter.writeSubgraph(&builder, sg, 0, edgeOwners([]*Subgraph{sg}))
 
-	// Start subgraph declaration
-	// Anonymous subgraphs (empty name) don't include a quoted name
-	if sg.name == "" {
-		builder.WriteString("subgraph {\n")
-	} else {
-		builder.WriteString(fmt.Sprintf("subgraph %s {\n", quoteDOTID(sg.name)))
-	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
 
-	// Add subgraph attributes
-	if sg.attrs != nil {
-		attrs := sg.attrs.List()
-		if len(attrs) > 0 {
-			for _, attr := range attrs {
-				builder.WriteString(fmt.Sprintf("\t\t%s;\n", attr))
+// edgeOwners maps each edge held by the given subgraphs, or their nested subgraphs, to the
+// subgraph whose block it is rendered in. An edge held by several subgraphs belongs to
+// the first one rendered, and nested subgraphs are rendered before their parents' contents.
+func edgeOwners(subgraphs []*Subgraph) map[*Edge]*Subgraph {
+	owners := make(map[*Edge]*Subgraph)
+
+	var visit func(sg *Subgraph)
+	visit = func(sg *Subgraph) {
+		for _, nested := range sg.subgraphs {
+			visit(nested)
+		}
+		for edge := range sg.edges.all() {
+			if owners[edge] == nil {
+				owners[edge] = sg
 			}
 		}
 	}
 
-	// Add nodes
-	for _, node := range sg.nodes {
-		builder.WriteString(fmt.Sprintf("\t\t%s;\n", node))
+	for _, sg := range subgraphs {
+		visit(sg)
 	}
 
-	// Add edges
-	for _, edge := range sg.edges {
-		builder.WriteString(fmt.Sprintf("\t\t%s;\n", edge.ToString(sg.parent.IsDirected())))
-	}
-
-	builder.WriteString("\t}")
-
-	return builder.String()
+	return owners
 }
@@ -3,7 +3,6 @@
 package goraffe
 
 import (
-	"fmt"
 	"maps"
 )
 
@@ -44,6 +43,14 @@ func (a *SubgraphAttributes) setCustom(key, value string) {
 	a.custom[key] = value
 }
 
+// clone returns a copy of the attributes with its own custom attribute map.
+func (a *SubgraphAttributes) clone() *SubgraphAttributes {
+	copied := *a
+	copied.custom = maps.Clone(a.custom)
+
+	return &copied
+}
+
 // Label returns the subgraph label. Returns empty string if unset.
 // Note: An empty string return value may indicate either an unset label or a label
 // explicitly set to empty string.
@@ -126,39 +133,41 @@ func (a *SubgraphAttributes) Rank() Rank {
 // List returns a slice of DOT attribute strings for rendering.
 // Only attributes that have been explicitly set are included.
 func (a SubgraphAttributes) List() []string {
-	attrs := make([]string, 0)
+	return formatAttributes(a.attributes())
+}
+
+// attributes returns the attributes that have been explicitly set, typed attributes
+// first in declaration order followed by custom attributes sorted by name.
+func (a SubgraphAttributes) attributes() []attribute {
+	attrs := make([]attribute, 0)
 
 	if a.label != nil {
-		attrs = append(attrs, fmt.Sprintf(`label="%s"`, escapeDOTString(a.Label())))
+		attrs = append(attrs, attribute{name: "label", value: a.Label()})
 	}
 
 	if a.style != nil {
-		attrs = append(attrs, fmt.Sprintf(`style="%s"`, escapeDOTString(a.Style())))
+		attrs = append(attrs, attribute{name: "style", value: a.Style()})
 	}
 
 	if a.color != nil {
-		attrs = append(attrs, fmt.Sprintf(`color="%s"`, escapeDOTString(a.Color())))
+		attrs = append(attrs, attribute{name: "color", value: a.Color()})
 	}
 
 	if a.fillColor != nil {
-		attrs = append(attrs, fmt.Sprintf(`fillcolor="%s"`, escapeDOTString(a.FillColor())))
+		attrs = append(attrs, attribute{name: "fillcolor", value: a.FillColor()})
 	}
 
 	if a.fontName != nil {
-		attrs = append(attrs, fmt.Sprintf(`fontname="%s"`, escapeDOTString(a.FontName())))
+		attrs = append(attrs, attribute{name: "fontname", value: a.FontName()})
 	}
 
 	if a.fontSize != nil {
-		attrs = append(attrs, fmt.Sprintf(`fontsize="%g"`, a.FontSize()))
+		attrs = append(attrs, attribute{name: "fontsize", value: formatFloat(a.FontSize())})
 	}
 
 	if a.rank != nil {
-		attrs = append(attrs, fmt.Sprintf(`rank="%s"`, a.Rank()))
-	}
-
-	for k, v := range a.custom {
-		attrs = append(attrs, fmt.Sprintf(`%s="%s"`, k, escapeDOTString(v)))
+		attrs = append(attrs, attribute{name: "rank", value: string(a.Rank())})
 	}
 
-	return attrs
+	return appendCustom(attrs, a.custom)
 }
@@ -389,3 +389,96 @@ func TestSubgraph_SetRank_AllValues(t *testing.T) {
 		})
 	}
 }
+
+func TestSubgraph_SetDefaultNodeAttrs(t *testing.T) {
+	asrt := assert.New(t)
+
+	g := NewGraph()
+	var sg *Subgraph
+	g.Subgraph("cluster_0", func(s *Subgraph) {
+		sg = s
+		s.SetDefaultNodeAttrs(WithBoxShape(), WithColor("red"))
+	})
+
+	asrt.Equal(ShapeBox, sg.DefaultNodeAttrs().Shape(), "expected default node shape to be box")
+	asrt.Equal("red", sg.DefaultNodeAttrs().Color(), "expected default node color to be red")
+	asrt.Empty(g.DefaultNodeAttrs().Shape(), "expected graph defaults to be unaffected")
+
+	sg.SetDefaultNodeAttrs(WithCircleShape())
+	asrt.Equal(ShapeCircle, sg.DefaultNodeAttrs().Shape())
+	asrt.Empty(sg.DefaultNodeAttrs().Color(), "expected SetDefaultNodeAttrs to replace earlier defaults")
+}
+
+func TestSubgraph_SetDefaultEdgeAttrs(t *testing.T) {
+	asrt := assert.New(t)
+
+	g := NewGraph()
+	var sg *Subgraph
+	g.Subgraph("cluster_0", func(s *Subgraph) {
+		sg = s
+		s.SetDefaultEdgeAttrs(WithEdgeColor("blue"), WithEdgeStyle(EdgeStyleDashed))
+	})
+
+	asrt.Equal("blue", sg.DefaultEdgeAttrs().Color(), "expected default edge color to be blue")
+	asrt.Equal(EdgeStyleDashed, sg.DefaultEdgeAttrs().Style(), "expected default edge style to be dashed")
+	asrt.Empty(g.DefaultEdgeAttrs().Color(), "expected graph defaults to be unaffected")
+}
+
+func TestSubgraph_DefaultAttrs_Lazy(t *testing.T) {
+	asrt := assert.New(t)
+
+	g := NewGraph()
+	sg := g.Subgraph("sub", func(s *Subgraph) {})
+
+	asrt.NotNil(sg.DefaultNodeAttrs(), "expected DefaultNodeAttrs() to return non-nil attributes")
+	asrt.Same(sg.DefaultNodeAttrs(), sg.DefaultNodeAttrs(), "expected DefaultNodeAttrs() to return the same instance")
+	asrt.NotNil(sg.DefaultEdgeAttrs(), "expected DefaultEdgeAttrs() to return non-nil attributes")
+	asrt.NotContains(g.String(), "node [", "expected empty defaults not to be rendered")
+}
+
+func TestSubgraph_Nodes_InsertionOrder(t *testing.T) {
+	t.Run("returns nodes in the order they were added", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph(Directed)
+		sg := g.Subgraph("cluster_0", func(s *Subgraph) {
+			_ = s.AddNode(NewNode("C"))
+			_ = s.AddNode(NewNode("A"))
+			_, _ = s.AddEdge(NewNode("D"), NewNode("B"))
+		})
+
+		asrt.Equal([]string{"C", "A", "D", "B"}, nodeIDs(sg.Nodes()))
+	})
+
+	t.Run("re-adding a node keeps its position", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		replacement := NewNode("A", WithColor("red"))
+		sg := g.Subgraph("sub", func(s *Subgraph) {
+			_ = s.AddNode(NewNode("A"))
+			_ = s.AddNode(NewNode("B"))
+			_ = s.AddNode(replacement)
+		})
+
+		asrt.Equal([]string{"A", "B"}, nodeIDs(sg.Nodes()))
+		asrt.Same(replacement, sg.Nodes()[0])
+	})
+
+	t.Run("removing a node preserves the order of the rest", func(t *testing.T) {
+		asrt := assert.New(t)
+
+		g := NewGraph()
+		sg := g.Subgraph("sub", func(s *Subgraph) {
+			for _, id := range []string{"A", "B", "C", "D"} {
+				_ = s.AddNode(NewNode(id))
+			}
+		})
+
+		g.RemoveNode("B")
+		_ = sg.AddNode(NewNode("E"))
+
+		asrt.Equal([]string{"A", "C", "D", "E"}, nodeIDs(sg.Nodes()))
+		asrt.Equal("subgraph \"sub\" {\n\t\"A\";\n\t\"C\";\n\t\"D\";\n\t\"E\";\n}", sg.String())
+	})
+}